@@ -0,0 +1,6 @@
+#!/bin/sh
+
+### cron ###
+chmod +x /bin/backupmx-recipients
+systemctl daemon-reload
+systemctl restart cron
@@ -0,0 +1,19 @@
+#!/bin/sh
+
+### backup mx ###
+# lookup tables (the fetched recipient maps are kept across installations)
+mkdir -p /etc/postfix/recipients
+touch /etc/postfix/recipients/mailcow_recipients /etc/postfix/recipients/simplelogin_recipients \
+    /etc/postfix/recipients/simplelogin_recipients.pcre
+postmap /etc/postfix/transport
+postmap /etc/postfix/relay_recipients
+postmap /etc/postfix/tls_policy
+postfix check
+
+# monitoring
+echo 'ARGS="--web.listen-address=:9099 --systemd.enable"' > /etc/default/prometheus-postfix-exporter
+
+# restart services
+systemctl daemon-reload
+systemctl enable postfix prometheus-postfix-exporter
+systemctl restart postfix prometheus-postfix-exporter
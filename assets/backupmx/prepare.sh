@@ -0,0 +1,7 @@
+#!/bin/sh
+
+### backup mx ###
+# install pre-requisites
+apt-get update
+echo "postfix postfix/main_mailer_type select No configuration" | debconf-set-selections
+DEBIAN_FRONTEND=noninteractive apt-get install --yes postfix ssl-cert prometheus-postfix-exporter
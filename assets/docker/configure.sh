@@ -0,0 +1,10 @@
+#!/bin/sh
+
+### docker ###
+# daemon.json
+cat << EOF > /etc/docker/daemon.json
+{{ .daemonJson }}
+EOF
+
+# apply the registry mirrors without restarting the containers
+systemctl reload docker
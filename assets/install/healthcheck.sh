@@ -0,0 +1,53 @@
+#!/bin/sh
+
+### health check ###
+# usage: healthcheck.sh <directory> <url> [smtp hostname]
+# waits until all containers of the compose project are healthy, the url answers with 200 through traefik,
+# and SMTP greets with the hostname (if given); prints the container logs if the checks time out
+directory="$1"
+url="$2"
+smtp="${3:-}"
+timeout="${HEALTHCHECK_TIMEOUT:-600}"
+
+# install pre-requisites, if missing
+if ! command -v jq > /dev/null || ! command -v nc > /dev/null; then
+    apt-get update
+    DEBIAN_FRONTEND=noninteractive apt-get install --yes jq netcat-openbsd
+fi
+
+cd "${directory}" || exit 1
+host=$(echo "${url}" | sed -E 's|^https://([^/:]+).*$|\1|')
+deadline=$(( $(date +%s) + timeout ))
+
+while [ "$(date +%s)" -lt "${deadline}" ]; do
+    sleep 10
+
+    containers=$(docker compose ps --all --format json)
+    running=$(echo "${containers}" | jq -r 'select(.State == "running") | .Service')
+    status=""
+    banner=""
+    unhealthy=$(echo "${containers}" | jq -r 'select((.State == "running" and .Health != "" and .Health != "healthy") or (.State != "running" and .ExitCode != 0)) | .Service' | tr '\n' ' ')
+    if [ -z "${running}" ] || [ -n "${unhealthy}" ]; then
+        continue
+    fi
+
+    status=$(curl --silent --insecure --location --max-time 10 --output /dev/null --write-out "%{http_code}" --resolve "${host}:443:127.0.0.1" "${url}" || true)
+    if [ "${status}" != "200" ]; then
+        continue
+    fi
+
+    if [ -n "${smtp}" ]; then
+        banner=$(printf 'QUIT\r\n' | nc -w 10 127.0.0.1 25 | head -n 1)
+        if ! echo "${banner}" | grep -q "^220 ${smtp} "; then
+            continue
+        fi
+    fi
+
+    echo "healthy"
+    exit 0
+done
+
+echo "$(basename "${directory}") did not become healthy within ${timeout}s (unhealthy containers: ${unhealthy:-none}, ${url}: ${status:-none}, SMTP banner: ${banner:-none})" >&2
+docker compose ps --all >&2 || true
+docker compose logs --tail 50 >&2 || true
+exit 1
@@ -0,0 +1,15 @@
+#!/bin/sh
+
+### mailcow API readiness ###
+# wait for the API to answer (unauthenticated requests are answered with 401 once PHP is up)
+for _ in $(seq 1 60); do
+    status=$(curl --silent --insecure --output /dev/null --write-out "%{http_code}" https://127.0.0.1:8443/api/v1/get/status/version)
+    if [ "${status}" = "200" ] || [ "${status}" = "401" ]; then
+        echo "ready"
+        exit 0
+    fi
+    sleep 5
+done
+
+echo "mailcow API did not become available"
+exit 1
@@ -1,6 +1,6 @@
 #!/bin/sh
 
 ### cron ###
-chmod +x /bin/mailcow-backup
+chmod +x /bin/mailcow-backup /bin/mailcow-recipients
 systemctl daemon-reload
 systemctl restart cron
@@ -1,4 +1,9 @@
 #!/bin/sh
 
+# relay recipient maps of the SimpleLogin alias domains, filled by /bin/simplelogin-recipients
+touch /opt/mailcow/data/conf/postfix/simplelogin_recipients /opt/mailcow/data/conf/postfix/simplelogin_recipients.pcre
+# recipients fetched by the backup MX relay server, filled by /bin/mailcow-recipients
+touch /opt/mailcow/data/conf/postfix/mailcow_recipients
+
 # restart services
 systemctl restart mailcow
@@ -9,4 +9,4 @@ git clone https://github.com/mailcow/mailcow-dockerized /opt/mailcow || true
 
 # install pre-requisites
 apt-get update
-DEBIAN_FRONTEND=noninteractive apt-get install --yes jq
+DEBIAN_FRONTEND=noninteractive apt-get install --yes jq netcat-openbsd
@@ -0,0 +1,27 @@
+#!/bin/sh
+
+### registry mirror ###
+cd /opt/mirror
+
+# verify the images before they are started
+if ! sh /opt/mirror/verify-images.sh; then
+    exit 1
+fi
+
+# start services
+systemctl daemon-reload
+systemctl enable mirror
+systemctl restart mirror
+
+# wait until the mirror answers; docker pulls from Docker Hub directly while it doesn't
+for _ in $(seq 1 30); do
+    if curl --silent --fail --max-time 5 --output /dev/null http://127.0.0.1:5000/v2/; then
+        echo "healthy"
+        exit 0
+    fi
+    sleep 5
+done
+
+echo "the registry mirror did not become healthy" >&2
+docker compose logs --tail 50 >&2 || true
+exit 1
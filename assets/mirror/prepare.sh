@@ -0,0 +1,5 @@
+#!/bin/sh
+
+### registry mirror ###
+# create directories
+mkdir -p /opt/mirror || true
@@ -0,0 +1,8 @@
+#!/bin/sh
+
+### probe ###
+# create directories
+mkdir -p /opt/probe || true
+
+# install pre-requisites
+command -v python3 > /dev/null || (apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install --yes python3)
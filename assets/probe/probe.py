@@ -0,0 +1,174 @@
+#!/usr/bin/env python3
+
+### end-to-end mail flow probe ###
+# sends a message from the probe mailbox to itself and to a SimpleLogin alias, waits for them via IMAP,
+# verifies the authentication results and the TLS on the hops, and prints the outcome as JSON
+# the configuration is read from stdin to keep the credentials off the command line
+
+import email
+import email.policy
+import email.utils
+import imaplib
+import ipaddress
+import json
+import re
+import smtplib
+import ssl
+import sys
+import time
+import urllib.request
+import uuid
+from datetime import datetime, timezone
+from email.message import EmailMessage
+
+TIMEOUT = 300
+INTERVAL = 10
+FOLDERS = ["INBOX", "Junk"]
+
+
+def send(config, recipient, token):
+    message = EmailMessage()
+    message["From"] = config["mailbox"]
+    message["To"] = recipient
+    message["Subject"] = f"mail probe {token}"
+    message["Message-ID"] = email.utils.make_msgid(domain=config["mailbox"].split("@")[1])
+    message.set_content(f"end-to-end mail flow probe {token}")
+
+    with smtplib.SMTP(config["mailname"], 587, timeout=30) as smtp:
+        smtp.starttls(context=ssl.create_default_context())
+        smtp.login(config["mailbox"], config["password"])
+        smtp.send_message(message)
+
+
+def receive(config, tokens):
+    found = {}
+    deadline = time.time() + TIMEOUT
+    imap = imaplib.IMAP4_SSL(config["mailname"], 993, ssl_context=ssl.create_default_context())
+    imap.login(config["mailbox"], config["password"])
+    try:
+        while time.time() < deadline and len(found) < len(tokens):
+            time.sleep(INTERVAL)
+            for folder in FOLDERS:
+                if imap.select(folder)[0] != "OK":
+                    continue
+                for name, token in tokens.items():
+                    if name in found:
+                        continue
+                    _, ids = imap.search(None, "SUBJECT", f'"{token}"')
+                    for message_id in ids[0].split():
+                        _, data = imap.fetch(message_id, "(RFC822.HEADER)")
+                        found[name] = {
+                            "folder": folder,
+                            "headers": email.message_from_bytes(data[0][1], policy=email.policy.default),
+                        }
+                        imap.store(message_id, "+FLAGS", "\\Deleted")
+                imap.expunge()
+    finally:
+        imap.logout()
+    return found
+
+
+def authentication_results(headers):
+    results = {}
+    for header in headers.get_all("Authentication-Results", []):
+        for method in ["dkim", "spf", "dmarc"]:
+            match = re.search(rf"\b{method}=(\w+)", str(header))
+            if match and results.get(method) != "pass":
+                results[method] = match.group(1)
+    return results
+
+
+def is_internal(received):
+    for address in re.findall(r"\[([0-9a-fA-F:.]+)\]", received):
+        try:
+            if not ipaddress.ip_address(address).is_private and not ipaddress.ip_address(address).is_loopback:
+                return False
+        except ValueError:
+            return False
+    return True
+
+
+def tls_hops(headers):
+    hops = []
+    for received in headers.get_all("Received", []):
+        received = " ".join(str(received).split())
+        if not re.search(r"\bwith E?SMTP", received) or is_internal(received):
+            continue
+        hops.append({
+            "hop": received.split(";")[0][:120],
+            "tls": bool(re.search(r"\bwith ESMTPS|\bwith ESMTPSA|using TLS", received)),
+        })
+    return hops
+
+
+def verify(name, found, authenticated):
+    if name not in found:
+        return {"delivered": False, "passed": False}
+
+    headers = found[name]["headers"]
+    results = authentication_results(headers)
+    hops = tls_hops(headers)
+    passed = found[name]["folder"] == "INBOX" and all(hop["tls"] for hop in hops)
+    if authenticated:
+        passed = passed and all(results.get(method) == "pass" for method in ["dkim", "spf", "dmarc"])
+    return {
+        "delivered": True,
+        "folder": found[name]["folder"],
+        "dkim": results.get("dkim", "none"),
+        "spf": results.get("spf", "none"),
+        "dmarc": results.get("dmarc", "none"),
+        "tls": all(hop["tls"] for hop in hops),
+        "hops": hops,
+        "passed": passed,
+    }
+
+
+def notify(config, outcome):
+    ntfy = config.get("ntfy")
+    if not ntfy:
+        return
+    failed = [name for name, result in outcome["messages"].items() if not result["passed"]]
+    request = urllib.request.Request(
+        ntfy["url"],
+        data=json.dumps({
+            "topic": ntfy["topic"],
+            "title": f"mail probe {outcome['status']}",
+            "message": f"failed: {', '.join(failed)}" if failed else "all messages were delivered and verified",
+            "priority": 3 if outcome["status"] == "passed" else 5,
+            "tags": ["white_check_mark" if outcome["status"] == "passed" else "rotating_light"],
+        }).encode(),
+        headers={"Authorization": f"Bearer {ntfy['token']}", "Content-Type": "application/json"},
+    )
+    try:
+        urllib.request.urlopen(request, timeout=30)
+    except OSError as error:
+        print(f"failed to notify ntfy: {error}", file=sys.stderr)
+
+
+def main():
+    config = json.load(sys.stdin)
+    token = uuid.uuid4().hex
+    recipients = {"self": config["mailbox"]}
+    if config.get("alias"):
+        recipients["alias"] = config["alias"]
+    tokens = {name: f"{token}-{name}" for name in recipients}
+
+    outcome = {"timestamp": datetime.now(timezone.utc).isoformat(), "messages": {}}
+    try:
+        for name, recipient in recipients.items():
+            send(config, recipient, tokens[name])
+        found = receive(config, tokens)
+        # messages to the own mailbox are authenticated by the submission and not checked for DKIM, SPF, and DMARC
+        outcome["messages"] = {name: verify(name, found, name != "self") for name in recipients}
+    except (OSError, smtplib.SMTPException, imaplib.IMAP4.error) as error:
+        outcome["error"] = str(error)
+        outcome["messages"] = {name: {"delivered": False, "passed": False} for name in recipients}
+
+    passed = "error" not in outcome and all(result["passed"] for result in outcome["messages"].values())
+    outcome["status"] = "passed" if passed else "failed"
+    notify(config, outcome)
+    print(json.dumps(outcome))
+
+
+if __name__ == "__main__":
+    main()
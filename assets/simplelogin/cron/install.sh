@@ -1,6 +1,6 @@
 #!/bin/sh
 
 ### cron ###
-chmod +x /bin/simplelogin-backup
+chmod +x /bin/simplelogin-backup /bin/simplelogin-wal-push /bin/simplelogin-restore /bin/simplelogin-recipients
 systemctl daemon-reload
 systemctl restart cron
@@ -5,8 +5,10 @@ echo "[init] setting env..."
 set -o allexport && source /code/.env && set +o allexport
 
 # needed for importing gpg keys
-echo "[init] creating gpg directory..."
-mkdir /sl/gnupg || true
+if [ "$PGP_ENABLED" = "true" ]; then
+    echo "[init] creating gpg directory..."
+    mkdir /sl/gnupg || true
+fi
 
 # application initialization
 echo "[init] initializing application..."
@@ -14,7 +16,16 @@ alembic upgrade head
 python init_app.py
 
 # set all users as premium users
-echo "[init] set users as premium..."
-apt-get update
-apt-get install --yes postgresql-client
-psql -c "UPDATE users SET lifetime = TRUE;"
+if [ "$PREMIUM_BY_DEFAULT" = "true" ]; then
+    echo "[init] set users as premium..."
+    apt-get update
+    apt-get install --yes postgresql-client
+    psql -c "UPDATE users SET lifetime = TRUE;"
+fi
+
+# disable the pgp encryption of all mailboxes
+if [ "$PGP_ENABLED" != "true" ]; then
+    echo "[init] disable pgp of mailboxes..."
+    command -v psql > /dev/null || { apt-get update && apt-get install --yes postgresql-client; }
+    psql -c "UPDATE mailbox SET disable_pgp = TRUE;"
+fi
@@ -0,0 +1,89 @@
+#!/bin/sh
+
+### postgres major version upgrade ###
+# usage: postgres-upgrade.sh <desired major version>
+# migrates the database with a dump and restore if the data directory belongs to another major version
+# exit codes: 0 if there is nothing to migrate, 2 if the database was migrated, 1 if the migration failed
+# on failure, the previous data directory is put back and simplelogin stays stopped
+cd /opt/simplelogin
+
+DESIRED="$1"
+PGROOT=/opt/simplelogin/postgres
+
+# the desired major version is derived from the image tag, which may not carry one
+case "$DESIRED" in
+    '' | *[!0-9]* | 0*)
+        echo "the desired postgres major version '${DESIRED}' is not a positive integer; refusing to upgrade" >&2
+        exit 1
+        ;;
+esac
+
+VERSION_FILE=$(find "$PGROOT" -maxdepth 3 -name PG_VERSION 2>/dev/null | head -n 1)
+if [ -z "$VERSION_FILE" ]; then
+    # fresh installation
+    exit 0
+fi
+CURRENT=$(cat "$VERSION_FILE")
+if [ "$CURRENT" = "$DESIRED" ]; then
+    exit 0
+fi
+if [ "$CURRENT" -gt "$DESIRED" ]; then
+    echo "postgres cannot be downgraded from ${CURRENT} to ${DESIRED}" >&2
+    exit 1
+fi
+echo "upgrading postgres from ${CURRENT} to ${DESIRED}..."
+
+# the running container still serves the current major version
+RUNNING=$(docker exec simplelogin-postgres psql -U simplelogin -d simplelogin -tAc "SHOW server_version_num" 2>/dev/null)
+if [ -z "$RUNNING" ] || [ "$((RUNNING / 10000))" != "$CURRENT" ]; then
+    echo "postgres ${CURRENT} must be running to dump the database; refusing to upgrade" >&2
+    exit 1
+fi
+
+# backup and dump with the current major version
+TIMESTAMP=$(date +%Y%m%d_%H%M%S)
+DUMP="simplelogin-upgrade-${CURRENT}-to-${DESIRED}-${TIMESTAMP}.dump"
+if ! /bin/simplelogin-backup; then
+    echo "the backup before the postgres upgrade failed; refusing to upgrade" >&2
+    exit 1
+fi
+if ! docker exec simplelogin-postgres pg_dump -U simplelogin -d simplelogin -Fc -f "/backups/${DUMP}"; then
+    echo "the dump of postgres ${CURRENT} failed; refusing to upgrade" >&2
+    exit 1
+fi
+SCHEMA=$(docker exec simplelogin-postgres psql -U simplelogin -d simplelogin -tAc "SELECT version_num FROM alembic_version")
+
+# keep the current data directory until the migration succeeded
+systemctl stop simplelogin
+docker compose down
+PREVIOUS="${PGROOT}.pg${CURRENT}-${TIMESTAMP}"
+mv "$PGROOT" "$PREVIOUS"
+mkdir -p "$PGROOT"
+
+rollback() {
+    echo "$1; restoring the postgres ${CURRENT} data directory" >&2
+    docker compose logs --tail 50 postgres >&2
+    docker compose down
+    rm -rf "$PGROOT"
+    mv "$PREVIOUS" "$PGROOT"
+    echo "simplelogin stays stopped: revert the postgres image to ${CURRENT} or restore the snapshot" >&2
+    exit 1
+}
+
+# restore into the new major version
+docker compose up -d --wait postgres || rollback "postgres ${DESIRED} did not start"
+docker exec simplelogin-postgres pg_restore -U simplelogin -d simplelogin --no-owner --no-privileges --role=simplelogin --exit-on-error "/backups/${DUMP}" ||
+    rollback "the restore into postgres ${DESIRED} failed"
+
+# verify the migrated database
+MIGRATED=$(docker exec simplelogin-postgres psql -U simplelogin -d simplelogin -tAc "SHOW server_version_num")
+[ "$((MIGRATED / 10000))" = "$DESIRED" ] || rollback "postgres ${DESIRED} is not serving the database"
+[ "$(docker exec simplelogin-postgres psql -U simplelogin -d simplelogin -tAc "SELECT version_num FROM alembic_version")" = "$SCHEMA" ] ||
+    rollback "the migrated schema doesn't match"
+
+# the base backups and the archived WAL of the previous major version can't be replayed by the new one
+/bin/simplelogin-backup || true
+
+docker compose down
+echo "upgraded postgres from ${CURRENT} to ${DESIRED}; the previous data directory is kept in ${PREVIOUS}"
+exit 2
@@ -3,4 +3,8 @@
 ### simplelogin ###
 # create directories
 mkdir -p /opt/simplelogin || true
+mkdir -p /opt/simplelogin/upload || true
 mkdir -p /opt/backup/simplelogin || true
+# the postgres user (uid 70) archives the WAL
+mkdir -p /opt/backup/simplelogin/wal || true
+chown 70:70 /opt/backup/simplelogin/wal
@@ -1,6 +1,11 @@
 #!/bin/sh
 
 ### traefik ###
+# verify the images before they are started
+if ! sh /opt/traefik/verify-images.sh; then
+    exit 1
+fi
+
 systemctl daemon-reload
 systemctl enable traefik
 systemctl restart traefik
@@ -0,0 +1,74 @@
+// Command mailcow-object applies a lifecycle action to a mailcow object via the local mailcow API.
+// It is installed on the mailcow server and run by the Pulumi resources managing the mailcow objects,
+// which pass the JSON encoded request (the read-write API key, the object, and the password of a mailbox) on stdin.
+//
+// Usage: mailcow-object create|update|delete
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object"
+)
+
+// apiURL is the base URL of the local mailcow API, which is only reachable from the server itself.
+const apiURL = "https://127.0.0.1:8443/api/v1"
+
+// requestTimeout is the timeout of a single API request.
+const requestTimeout = 30 * time.Second
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "mailcow-object: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run applies the lifecycle action given as the only argument to the requested object.
+// args: The command line arguments.
+func run(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf(
+			"usage: mailcow-object %s|%s|%s",
+			object.ActionCreate,
+			object.ActionUpdate,
+			object.ActionDelete,
+		)
+	}
+	action := object.Action(args[0])
+	switch action {
+	case object.ActionCreate, object.ActionUpdate, object.ActionDelete:
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	var request object.Request
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	if request.Object == nil {
+		return fmt.Errorf("invalid request: missing object")
+	}
+
+	// the certificate of the local API is issued for the mail hostname, so it isn't verified (like curl --insecure)
+	httpClient := &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			//nolint:gosec // the API is only reached via the loopback interface of the server
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	client := api.NewClient(apiURL, request.APIKey, api.WithHTTPClient(httpClient))
+	obj := request.Object
+	if err := object.Apply(context.Background(), client, action, obj, request.Password); err != nil {
+		return fmt.Errorf("%s %s %s: %w", action, obj.Kind, obj.Name, err)
+	}
+	return nil
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"fmt"
 
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/backupmx"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mirror"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/ntfy"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/probe"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/scaleway"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/scaleway/application"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/simplelogin"
@@ -21,7 +25,9 @@ import (
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/hetzner/server"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/traefik"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/dkim"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
 	serverModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/server"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
 )
 
 //nolint:gocognit,funlen // main is the entry point of the Pulumi program.
@@ -58,12 +64,32 @@ func main() {
 		dependsOn := []pulumi.Resource{instance.Resource}
 
 		// docker
-		dockerInstall, doErr := docker.Install(ctx, instance.SSHIPv4, sshKey.PrivateKeyPem, pulumi.DependsOn(dependsOn))
+		dockerInstall, doErr := docker.Install(ctx, instance.SSHIPv4, sshKey.PrivateKeyPem, serverConfig, pulumi.DependsOn(dependsOn))
 		if doErr != nil {
 			return doErr
 		}
 		dependsOn = append(dependsOn, dockerInstall)
 
+		// image inventories, by component
+		images := map[string]pulumi.MapOutput{}
+
+		// registry mirror
+		if serverUtil.MirrorEnabled(serverConfig) {
+			mirrorInstall, mirrorImages, mrErr := mirror.Install(
+				ctx,
+				instance.SSHIPv4,
+				sshKey.PrivateKeyPem,
+				serverConfig,
+				scalewayConfig,
+				pulumi.DependsOn(dependsOn),
+			)
+			if mrErr != nil {
+				return mrErr
+			}
+			dependsOn = append(dependsOn, mirrorInstall)
+			images["mirror"] = mirrorImages
+		}
+
 		// google cloud
 		serviceAccount, saErr := serviceaccount.Create(ctx, dnsConfig)
 		if saErr != nil {
@@ -100,65 +126,113 @@ func main() {
 		dependsOn = append(dependsOn, scalewayInstall)
 
 		// traefik
-		traefikInstall, tErr := traefik.Install(
+		traefikInstall, traefikImages, tErr := traefik.Install(
 			ctx,
 			instance.SSHIPv4,
 			sshKey.PrivateKeyPem,
 			dnsConfig,
+			serverConfig,
 			pulumi.DependsOn(dependsOn),
 		)
 		if tErr != nil {
 			return tErr
 		}
 		dependsOn = append(dependsOn, traefikInstall)
+		images["traefik"] = traefikImages
+
+		// ntfy
+		watchdogUser, wdErr := mailcow.CreateWatchdogNtfyUser(ctx, mailConfig)
+		if wdErr != nil {
+			return wdErr
+		}
+		probeUser, puErr := probe.CreateNtfyUser(ctx, mailConfig)
+		if puErr != nil {
+			return puErr
+		}
+		ntfyTask, ntfyImages, ntfyErr := ntfy.Install(
+			ctx,
+			instance.SSHIPv4,
+			sshKey.PrivateKeyPem,
+			ntfyConfig,
+			mailConfig,
+			dnsConfig,
+			serverConfig,
+			ntfyUsers(watchdogUser, probeUser),
+			pulumi.DependsOn(dependsOn),
+		)
+		if ntfyErr != nil {
+			return ntfyErr
+		}
+		images["ntfy"] = ntfyImages
 
 		// mailcow
-		mcErr := mailcow.Install(
+		mailcowSnapshot, mailboxPasswords, mailcowAPI, mailcowImages, mcErr := mailcow.Install(
 			ctx,
 			instance.PublicIPv4,
 			instance.PublicIPv6,
+			instance.ID,
 			instance.SSHIPv4,
 			sshKey.PrivateKeyPem,
 			mailcowSecrets,
 			mailConfig,
 			dnsConfig,
+			serverConfig,
+			ntfyConfig,
+			watchdogUser,
+			ntfyTask,
 			pulumi.DependsOn(dependsOn),
 		)
 		if mcErr != nil {
 			return mcErr
 		}
+		images["mailcow"] = mailcowImages
 		mcdErr := mailcow.CreateDNSRecords(ctx, mailConfig, instance.PublicIPv4, instance.PublicIPv6)
 		if mcdErr != nil {
 			return mcdErr
 		}
 
+		// backup mx
+		var backupMX *serverModel.Data
+		if backupmx.Enabled(mailConfig) {
+			var bmxErr error
+			backupMX, bmxErr = backupmx.Create(ctx, instance, sshKey.PrivateKeyPem, mailConfig, serverConfig, mailcowAPI)
+			if bmxErr != nil {
+				return bmxErr
+			}
+		}
+
 		// simplelogin
-		dkim, slErr := simplelogin.Install(
+		dkim, simpleloginSnapshot, simpleloginImages, slErr := simplelogin.Install(
 			ctx,
+			instance.ID,
 			instance.SSHIPv4,
 			sshKey.PrivateKeyPem,
 			simpleloginConfig,
 			serverConfig,
 			mailConfig,
 			dnsConfig,
+			scalewayConfig,
+			mailcowAPI,
 			pulumi.DependsOn(dependsOn),
 		)
 		if slErr != nil {
 			return slErr
 		}
+		images["simplelogin"] = simpleloginImages
 
-		// ntfy
-		ntfyErr := ntfy.Install(
+		// end-to-end probe
+		probeOutcome, prErr := probe.Run(
 			ctx,
 			instance.SSHIPv4,
 			sshKey.PrivateKeyPem,
-			ntfyConfig,
 			mailConfig,
-			dnsConfig,
+			ntfyConfig,
+			probeUser,
+			mailboxPasswords,
 			pulumi.DependsOn(dependsOn),
 		)
-		if ntfyErr != nil {
-			return ntfyErr
+		if prErr != nil {
+			return prErr
 		}
 
 		// write output files
@@ -166,33 +240,64 @@ func main() {
 		file.WriteAndUpload(ctx, "ssh.key", sshKey.PrivateKeyPem, 0o600)
 
 		// outputs
-		exportPulumiOutputs(ctx, instance, dkim)
+		exportPulumiOutputs(ctx, instance, backupMX, dkim, map[string]*hcloud.Snapshot{
+			"mailcow":     mailcowSnapshot,
+			"simplelogin": simpleloginSnapshot,
+		}, images, probeOutcome)
 
 		return nil
 	})
 }
 
+// ntfyUsers returns the provisioned Ntfy users.
+// users: The Ntfy users (optional ones may be nil).
+func ntfyUsers(users ...*ntfyModel.User) []*ntfyModel.User {
+	provisioned := []*ntfyModel.User{}
+	for _, user := range users {
+		if user != nil {
+			provisioned = append(provisioned, user)
+		}
+	}
+	return provisioned
+}
+
 // exportPulumiOutputs exports the necessary Pulumi outputs.
 // ctx: The Pulumi context.
 // instance: The Hetzner server instance data.
+// backupMX: The Hetzner server instance data of the backup MX relay server (optional).
 // dkim: The DKIM data.
+// snapshots: The snapshots taken before the remote installations, by component.
+// images: The image inventories of the docker-compose files, by component.
+// probeOutcome: The outcome of the end-to-end mail flow probe (optional).
 func exportPulumiOutputs(
 	ctx *pulumi.Context,
 	instance *serverModel.Data,
+	backupMX *serverModel.Data,
 	dkim *dkim.Data,
+	snapshots map[string]*hcloud.Snapshot,
+	images map[string]pulumi.MapOutput,
+	probeOutcome pulumi.Output,
 ) {
-	ctx.Export("server", pulumi.ToMap(map[string]any{
-		"network": map[string]any{
-			"public": map[string]any{
-				"ipv4": instance.PublicIPv4,
-				"ipv6": instance.PublicIPv6,
-				"ssh":  instance.SSHIPv4,
-			},
-			"private": map[string]any{
-				"ipv4": instance.PrivateIPv4,
-			},
-		},
-	}))
+	serverOutputs := map[string]any{
+		"network": networkOutputs(instance),
+	}
+	if backupMX != nil {
+		serverOutputs["backupMx"] = map[string]any{
+			"network": networkOutputs(backupMX),
+		}
+	}
+	snapshotOutputs := map[string]any{}
+	for component, snapshot := range snapshots {
+		snapshotOutputs[component] = snapshot.ID()
+	}
+	serverOutputs["snapshots"] = snapshotOutputs
+	ctx.Export("server", pulumi.ToMap(serverOutputs))
+
+	imageOutputs := map[string]any{}
+	for component, inventory := range images {
+		imageOutputs[component] = inventory
+	}
+	ctx.Export("images", pulumi.ToMap(imageOutputs))
 
 	ctx.Export("simplelogin", pulumi.ToMap(map[string]any{
 		"dkim": map[string]any{
@@ -200,4 +305,25 @@ func exportPulumiOutputs(
 			"privateKey": dkim.PrivateKey,
 		},
 	}))
+
+	if probeOutcome != nil {
+		ctx.Export("mail", pulumi.ToMap(map[string]any{
+			"probe": probeOutcome,
+		}))
+	}
+}
+
+// networkOutputs returns the network information of a server instance as Pulumi outputs.
+// instance: The Hetzner server instance data.
+func networkOutputs(instance *serverModel.Data) map[string]any {
+	return map[string]any{
+		"public": map[string]any{
+			"ipv4": instance.PublicIPv4,
+			"ipv6": instance.PublicIPv6,
+			"ssh":  instance.SSHIPv4,
+		},
+		"private": map[string]any{
+			"ipv4": instance.PrivateIPv4,
+		},
+	}
 }
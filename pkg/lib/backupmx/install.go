@@ -0,0 +1,119 @@
+package backupmx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+)
+
+// postfixConfigFiles is the list of Postfix configuration files rendered for the backup MX relay server.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var postfixConfigFiles = []string{"main.cf", "transport", "tls_policy", "relay_recipients"}
+
+// Install Postfix as backup MX relay on the remote server via SSH.
+// The recipients of the primary mail server are fetched periodically with the recipients key.
+// ctx: Pulumi context.
+// sshIPv4: The IPv4 address of the server to connect to via SSH.
+// privateKeyPem: The private key in PEM format to use for SSH authentication.
+// recipientsKey: The private key in OpenSSH format to fetch the recipients of the primary mail server with.
+// mailConfig: Mail configuration.
+// serverConfig: The configuration of the primary server.
+// dependsOn: Pulumi resource option to specify dependencies.
+func Install(
+	ctx *pulumi.Context,
+	sshIPv4 pulumi.StringOutput,
+	privateKeyPem pulumi.StringOutput,
+	recipientsKey pulumi.StringOutput,
+	mailConfig *mailConf.Config,
+	serverConfig *server.Config,
+	dependsOn pulumi.ResourceOrInvokeOption,
+) (*remote.Command, error) {
+	conn := &remote.ConnectionArgs{
+		Host:       sshIPv4,
+		PrivateKey: privateKeyPem,
+		User:       pulumi.String("root"),
+	}
+
+	opts := []pulumi.ResourceOption{dependsOn}
+
+	opts, prepErr := install.Prepare(ctx, "backupmx", conn, opts...)
+	if prepErr != nil {
+		return nil, prepErr
+	}
+
+	values := map[string]any{
+		"hostname":   mail.BackupMailname(*mailConfig.Main.Name),
+		"primary":    mail.Mailname(*mailConfig.Main.Name),
+		"domains":    mail.Domains(mailConfig),
+		"recipients": mail.BackupMXRecipients(mailConfig),
+	}
+
+	triggers := pulumi.Array{}
+	copies := []pulumi.Output{}
+	for _, name := range postfixConfigFiles {
+		content, rErr := template.Render(fmt.Sprintf("./assets/backupmx/%s.j2", name), values)
+		if rErr != nil {
+			return nil, rErr
+		}
+		configHash := file.WritePulumi(fmt.Sprintf("./outputs/backupmx_%s", name), pulumi.String(content)).
+			ApplyT(func(_ string) string {
+				hash, _ := file.Hash(fmt.Sprintf("./outputs/backupmx_%s", name))
+				return *hash
+			})
+		configCopy := configHash.ApplyT(func(_ string) pulumi.ResourceOption {
+			cmd, _ := remote.NewCopyToRemote(
+				ctx,
+				fmt.Sprintf("remote-copy-backupmx-%s", strings.ReplaceAll(name, ".", "-")),
+				&remote.CopyToRemoteArgs{
+					Source:     pulumi.NewFileAsset(fmt.Sprintf("./outputs/backupmx_%s", name)),
+					RemotePath: pulumi.Sprintf("/etc/postfix/%s", name),
+					Triggers:   pulumi.Array{configHash},
+					Connection: conn,
+				},
+				opts...)
+			return pulumi.DependsOn([]pulumi.Resource{cmd})
+		})
+		triggers = append(triggers, configHash)
+		copies = append(copies, configCopy)
+	}
+
+	keyCopy, kErr := remote.NewCommand(ctx, "remote-command-backupmx-recipients-key", &remote.CommandArgs{
+		Create:     pulumi.String("umask 077 && mkdir -p /root/.ssh && cat > /root/.ssh/backupmx-recipients"),
+		Update:     pulumi.String("umask 077 && mkdir -p /root/.ssh && cat > /root/.ssh/backupmx-recipients"),
+		Delete:     pulumi.String("rm -f /root/.ssh/backupmx-recipients"),
+		Stdin:      pulumi.ToSecret(recipientsKey).(pulumi.StringOutput),
+		Connection: conn,
+	}, opts...)
+	if kErr != nil {
+		return nil, kErr
+	}
+
+	cronCopies, cronErr := install.Cron(ctx, "backupmx", map[string]any{
+		"primary": *serverConfig.IPv4,
+	}, conn, append(opts, pulumi.DependsOn([]pulumi.Resource{keyCopy}))...)
+	if cronErr != nil {
+		return nil, cronErr
+	}
+	copies = append(copies, cronCopies...)
+
+	installFn, iErr := file.ReadContents("./assets/backupmx/install.sh")
+	if iErr != nil {
+		return nil, iErr
+	}
+	return remote.NewCommand(ctx, "remote-command-install-backupmx", &remote.CommandArgs{
+		Create:     pulumi.StringPtr(installFn),
+		Update:     pulumi.StringPtr(installFn),
+		Triggers:   triggers,
+		Connection: conn,
+	}, append(opts, install.CollectResourceOptions(copies)...)...)
+}
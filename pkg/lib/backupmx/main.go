@@ -0,0 +1,113 @@
+package backupmx
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/hetzner/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object/resource"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	serverConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	serverModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+)
+
+// nodeName is the name of the backup MX relay server node.
+const nodeName = "backup-mx"
+
+// Enabled returns whether the backup MX relay server is enabled in the mail configuration.
+// mailConfig: Mail configuration.
+func Enabled(mailConfig *mailConf.Config) bool {
+	return mail.BackupMXEnabled(mailConfig)
+}
+
+// Create creates the backup MX relay server, installs Postfix, creates the necessary DNS records,
+// authorizes it to fetch the recipients of the primary mail server, and adds the relay as forwarding host in mailcow.
+// ctx: Pulumi context.
+// primary: The data of the primary mail server.
+// privateKeyPem: The private key in PEM format to use for SSH authentication.
+// mailConfig: Mail configuration.
+// serverConfig: The configuration of the primary server.
+// mailcowAPI: The connection to the mailcow API.
+func Create(
+	ctx *pulumi.Context,
+	primary *serverModel.Data,
+	privateKeyPem pulumi.StringOutput,
+	mailConfig *mailConf.Config,
+	serverConfig *serverConf.Config,
+	mailcowAPI *resource.Connection,
+) (*serverModel.Data, error) {
+	instance, iErr := server.CreateNode(
+		ctx,
+		nodeName,
+		mail.BackupMailname(*mailConfig.Main.Name),
+		primary,
+		mailConfig.BackupMX.Server,
+	)
+	if iErr != nil {
+		return nil, iErr
+	}
+
+	recipientsKey, rkErr := createRecipientsKey(ctx, primary, privateKeyPem, mailConfig)
+	if rkErr != nil {
+		return nil, rkErr
+	}
+
+	_, instErr := Install(
+		ctx,
+		instance.SSHIPv4,
+		privateKeyPem,
+		recipientsKey,
+		mailConfig,
+		serverConfig,
+		pulumi.DependsOn([]pulumi.Resource{instance.Resource}),
+	)
+	if instErr != nil {
+		return nil, instErr
+	}
+
+	dnsErr := CreateDNSRecords(ctx, mailConfig, instance.PublicIPv4, instance.PublicIPv6)
+	if dnsErr != nil {
+		return nil, dnsErr
+	}
+
+	fwdErr := createForwardingHosts(ctx, instance, mailcowAPI)
+	if fwdErr != nil {
+		return nil, fwdErr
+	}
+
+	return instance, nil
+}
+
+// createForwardingHosts adds the public addresses of the backup MX relay server as forwarding hosts in mailcow,
+// so that relayed mail is not rejected by the IP based checks of the primary server.
+// ctx: Pulumi context.
+// instance: The data of the backup MX relay server.
+// mailcowAPI: The connection to the mailcow API.
+func createForwardingHosts(ctx *pulumi.Context, instance *serverModel.Data, mailcowAPI *resource.Connection) error {
+	addresses := map[string]pulumi.StringOutput{
+		"ipv4": instance.PublicIPv4,
+		"ipv6": instance.PublicIPv6,
+	}
+	for _, family := range []string{"ipv4", "ipv6"} {
+		forwardingHost := addresses[family].ApplyT(func(address string) *object.Object {
+			return &object.Object{
+				Kind: object.KindForwardingHost,
+				Name: address,
+			}
+		})
+		_, err := resource.ManageOutput(
+			ctx,
+			object.KindForwardingHost,
+			fmt.Sprintf("%s-%s", nodeName, family),
+			forwardingHost,
+			mailcowAPI,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package backupmx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/tls"
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	serverModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/server"
+)
+
+// recipientsKeyName is the name of the SSH key the backup MX relay server fetches the recipients with.
+const recipientsKeyName = "backupmx-recipients"
+
+// recipientsExport is the command the SSH key is restricted to on the primary mail server,
+// writing the recipient maps exported by mailcow and SimpleLogin as tar archive.
+const recipientsExport = "tar -C /opt/mailcow/data/conf/postfix -cf - " +
+	"mailcow_recipients simplelogin_recipients simplelogin_recipients.pcre"
+
+// createRecipientsKey creates the SSH key the backup MX relay server fetches the recipients with,
+// and authorizes it on the primary mail server, restricted to the export and the private address of the backup MX.
+// It returns the private key in OpenSSH format.
+// ctx: Pulumi context.
+// primary: The data of the primary mail server.
+// privateKeyPem: The private key in PEM format to use for SSH authentication.
+// mailConfig: Mail configuration.
+func createRecipientsKey(
+	ctx *pulumi.Context,
+	primary *serverModel.Data,
+	privateKeyPem pulumi.StringOutput,
+	mailConfig *mailConf.Config,
+) (pulumi.StringOutput, error) {
+	key, kErr := tls.CreateSSHKey(ctx, recipientsKeyName, 0)
+	if kErr != nil {
+		return pulumi.StringOutput{}, kErr
+	}
+
+	authorizedKey := key.PublicKeyOpenssh.ApplyT(func(publicKey string) string {
+		return fmt.Sprintf("restrict,from=\"%s\",command=\"%s\" %s %s",
+			*mailConfig.BackupMX.Server.IPv4, recipientsExport, strings.TrimSpace(publicKey), recipientsKeyName)
+	}).(pulumi.StringOutput)
+	removeKey := fmt.Sprintf("sed -i '/ %s$/d' /root/.ssh/authorized_keys", recipientsKeyName)
+	_, aErr := remote.NewCommand(ctx, "remote-command-authorize-backupmx-recipients", &remote.CommandArgs{
+		Create: pulumi.Sprintf("%s && cat >> /root/.ssh/authorized_keys", removeKey),
+		Update: pulumi.Sprintf("%s && cat >> /root/.ssh/authorized_keys", removeKey),
+		Delete: pulumi.String(removeKey),
+		Stdin:  pulumi.Sprintf("%s\n", authorizedKey),
+		Connection: &remote.ConnectionArgs{
+			Host:       primary.SSHIPv4,
+			PrivateKey: privateKeyPem,
+			User:       pulumi.String("root"),
+		},
+	}, pulumi.DependsOn([]pulumi.Resource{primary.Resource}))
+	if aErr != nil {
+		return pulumi.StringOutput{}, aErr
+	}
+
+	return key.PrivateKeyOpenssh, nil
+}
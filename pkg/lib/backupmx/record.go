@@ -0,0 +1,84 @@
+package backupmx
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/google/dns/record"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+)
+
+// primaryMXPriority is the MX priority of the primary mail server.
+const primaryMXPriority = 10
+
+// defaultBackupMXPriority is the default MX priority of the backup MX relay server.
+const defaultBackupMXPriority = 20
+
+// CreateDNSRecords creates the DNS records of the backup MX relay server and the MX records of all mail domains.
+// ctx: The Pulumi context for resource creation.
+// mailConfig: The mail configuration containing the domains.
+// ipv4: The public IPv4 address of the backup MX relay server.
+// ipv6: The public IPv6 address of the backup MX relay server.
+func CreateDNSRecords(
+	ctx *pulumi.Context,
+	mailConfig *mailConf.Config,
+	ipv4 pulumi.StringOutput,
+	ipv6 pulumi.StringOutput,
+) error {
+	priority := defaults.GetOrDefault(mailConfig.BackupMX.Priority, defaultBackupMXPriority)
+	if priority <= primaryMXPriority {
+		return fmt.Errorf(
+			"backup MX priority %d must be greater than the primary MX priority %d",
+			priority,
+			primaryMXPriority,
+		)
+	}
+
+	backupServer := mail.BackupMailname(*mailConfig.Main.Name)
+
+	_, v4Err := record.Create(ctx, &record.CreateOptions{
+		Domain:     backupServer,
+		ZoneID:     pulumi.String(*mailConfig.Main.ZoneID),
+		RecordType: "A",
+		Records:    pulumi.StringArray([]pulumi.StringInput{ipv4}),
+		Project:    mailConfig.Main.Project,
+	})
+	if v4Err != nil {
+		return v4Err
+	}
+
+	_, v6Err := record.Create(ctx, &record.CreateOptions{
+		Domain:     backupServer,
+		ZoneID:     pulumi.String(*mailConfig.Main.ZoneID),
+		RecordType: "AAAA",
+		Records:    pulumi.StringArray([]pulumi.StringInput{ipv6}),
+		Project:    mailConfig.Main.Project,
+	})
+	if v6Err != nil {
+		return v6Err
+	}
+
+	// the MX records of all domains list the primary server first and the backup server with a lower priority
+	records := pulumi.StringArray{
+		pulumi.Sprintf("%d %s.", primaryMXPriority, mail.Mailname(*mailConfig.Main.Name)),
+		pulumi.Sprintf("%d %s.", priority, backupServer),
+	}
+	for _, domain := range append([]*dns.DomainConfig{mailConfig.Main}, mailConfig.Additional...) {
+		_, mxErr := record.Create(ctx, &record.CreateOptions{
+			Domain:     *domain.Name,
+			ZoneID:     pulumi.String(*domain.ZoneID),
+			RecordType: "MX",
+			Records:    records,
+			Project:    domain.Project,
+		})
+		if mxErr != nil {
+			return mxErr
+		}
+	}
+
+	return nil
+}
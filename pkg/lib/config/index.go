@@ -62,12 +62,24 @@ func LoadConfig(
 
 	var serverConfig server.Config
 	cfg.RequireObject("server", &serverConfig)
+	if vErr := validateServerConfig(&serverConfig); vErr != nil {
+		return nil, nil, nil, nil, nil, nil, nil, vErr
+	}
 
 	var mailConfig mail.Config
 	cfg.RequireObject("mail", &mailConfig)
+	if vErr := validateMailConfig(&mailConfig); vErr != nil {
+		return nil, nil, nil, nil, nil, nil, nil, vErr
+	}
+	if vErr := validateBackupMXConfig(&mailConfig, &serverConfig, &networkConfig); vErr != nil {
+		return nil, nil, nil, nil, nil, nil, nil, vErr
+	}
 
 	var simpleloginConfig simplelogin.Config
 	cfg.RequireObject("simplelogin", &simpleloginConfig)
+	if vErr := validateSimpleloginConfig(&simpleloginConfig, &mailConfig); vErr != nil {
+		return nil, nil, nil, nil, nil, nil, nil, vErr
+	}
 
 	var ntfyConfig ntfy.Config
 	cfg.RequireObject("ntfy", &ntfyConfig)
@@ -82,3 +94,10 @@ func CommonLabels() map[string]string {
 		"purpose":     GlobalName,
 	}
 }
+
+// RequireSecret loads a secret value from the stack configuration.
+// ctx: The Pulumi context.
+// key: The configuration key (set with 'pulumi config set --secret <key> <value>').
+func RequireSecret(ctx *pulumi.Context, key string) pulumi.StringOutput {
+	return config.New(ctx, "").RequireSecret(key)
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/network"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+	mailUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+	simpleloginUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/simplelogin"
+)
+
+// validateMailConfig validates the mail configuration.
+// mailConfig: The mail configuration.
+func validateMailConfig(mailConfig *mail.Config) error {
+	validators := []func(*mail.Config) error{
+		mailUtil.ValidateSmarthosts,
+		mailUtil.ValidatePostfix,
+		mailUtil.ValidateCheckRules,
+		mailUtil.ValidateMailcowObjects,
+		mailUtil.ValidateRspamd,
+		mailUtil.ValidateRetention,
+		mailUtil.ValidateMailcowSettings,
+		mailUtil.ValidateMailcowVersion,
+		mailUtil.ValidateProbe,
+	}
+	for _, validate := range validators {
+		if vErr := validate(mailConfig); vErr != nil {
+			return vErr
+		}
+	}
+	return nil
+}
+
+// validateBackupMXConfig validates the backup MX relay server against the primary server and the network.
+// mailConfig: The mail configuration.
+// serverConfig: The server configuration.
+// networkConfig: The network configuration.
+func validateBackupMXConfig(mailConfig *mail.Config, serverConfig *server.Config, networkConfig *network.Config) error {
+	return mailUtil.ValidateBackupMX(mailConfig, serverConfig, networkConfig)
+}
+
+// validateServerConfig validates the server configuration.
+// serverConfig: The server configuration.
+func validateServerConfig(serverConfig *server.Config) error {
+	if serverConfig.SnapshotRetention != nil && *serverConfig.SnapshotRetention < 1 {
+		return fmt.Errorf("server snapshot retention must keep at least one snapshot")
+	}
+	if iErr := serverUtil.ValidateImages(serverConfig); iErr != nil {
+		return iErr
+	}
+	return serverUtil.ValidateMirror(serverConfig)
+}
+
+// validateSimpleloginConfig validates the SimpleLogin configuration.
+// simpleloginConfig: The SimpleLogin configuration.
+// mailConfig: The mail configuration.
+func validateSimpleloginConfig(simpleloginConfig *simplelogin.Config, mailConfig *mail.Config) error {
+	if aErr := simpleloginUtil.ValidateAliasDomains(simpleloginConfig, mailUtil.Domains(mailConfig)); aErr != nil {
+		return aErr
+	}
+
+	validators := []func(*simplelogin.Config) error{
+		simpleloginUtil.ValidateSettings,
+		simpleloginUtil.ValidateStorage,
+		simpleloginUtil.ValidateWebSettings,
+		simpleloginUtil.ValidateBackup,
+	}
+	for _, validate := range validators {
+		if vErr := validate(simpleloginConfig); vErr != nil {
+			return vErr
+		}
+	}
+	return nil
+}
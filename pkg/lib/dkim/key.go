@@ -0,0 +1,73 @@
+package dkim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/tls"
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	dkimModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/dkim"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/dns"
+)
+
+// keyLength defines the length of the DKIM RSA key.
+const keyLength = 2048
+
+// CreateKey creates a DKIM key pair and stores it in Vault.
+// ctx: The Pulumi context for resource creation.
+// name: The name of the key (the resource is named 'dkim-<name>').
+// secretKey: The key of the secret in Vault.
+func CreateKey(
+	ctx *pulumi.Context,
+	name string,
+	secretKey string,
+) (*dkimModel.Data, error) {
+	rsaKey, rsaErr := tls.CreateRSAKey(ctx, fmt.Sprintf("dkim-%s", name), keyLength)
+	if rsaErr != nil {
+		return nil, rsaErr
+	}
+
+	secretValue, _ := pulumi.All(rsaKey.PrivateKeyPem, rsaKey.PublicKeyPem).ApplyT(func(args []any) string {
+		privateKey := args[0].(string)
+		publicKey := args[1].(string)
+
+		value, _ := json.Marshal(map[string]string{
+			"private_key": privateKey,
+			"public_key":  publicKey,
+		})
+		return string(value)
+	}).(pulumi.StringOutput)
+	_, sErr := secret.Create(ctx, &secret.CreateOptions{
+		Path:  config.GlobalName,
+		Key:   secretKey,
+		Value: secretValue,
+	})
+	if sErr != nil {
+		return nil, sErr
+	}
+
+	publicKey, _ := rsaKey.PublicKeyPem.ApplyT(func(key string) string {
+		k := strings.ReplaceAll(key, "-----BEGIN PUBLIC KEY-----\n", "")
+		k = strings.ReplaceAll(k, "-----END PUBLIC KEY-----", "")
+		k = strings.TrimSpace(k)
+		ks := strings.Split(k, "\n")
+		return strings.Join(ks, "")
+	}).(pulumi.StringOutput)
+	return &dkimModel.Data{
+		Resource:   rsaKey,
+		PublicKey:  publicKey,
+		PrivateKey: rsaKey.PrivateKeyPem,
+	}, nil
+}
+
+// TXTRecord returns the DNS TXT record value publishing the DKIM public key.
+// publicKey: The DKIM public key.
+func TXTRecord(publicKey pulumi.StringOutput) pulumi.StringOutput {
+	return pulumi.Sprintf("v=DKIM1; k=rsa; t=s; s=email; p=%s", publicKey).ApplyT(func(value string) string {
+		return dns.SplitByLength(value, "TXT")
+	}).(pulumi.StringOutput)
+}
@@ -1,21 +1,25 @@
 package docker
 
 import (
-	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
 )
 
 // Install Docker on the remote server via SSH.
 // ctx: Pulumi context.
 // sshIPv4: The IPv4 address of the server to connect to via SSH.
 // privateKeyPem: The private key in PEM format to use for SSH authentication.
+// serverConfig: Server configuration.
 // dependsOn: Pulumi resource option to specify dependencies.
 func Install(
 	ctx *pulumi.Context,
 	sshIPv4 pulumi.StringOutput,
 	privateKeyPem pulumi.StringOutput,
+	serverConfig *server.Config,
 	dependsOn pulumi.ResourceOrInvokeOption,
 ) (*remote.Command, error) {
 	conn := &remote.ConnectionArgs{
@@ -24,7 +28,13 @@ func Install(
 		User:       pulumi.String("root"),
 	}
 
-	daemonJSON, dErr := file.ReadContents("./assets/docker/daemon.json")
+	mirror := ""
+	if serverUtil.MirrorEnabled(serverConfig) {
+		mirror = serverUtil.MirrorURL
+	}
+	daemonJSON, dErr := template.Render("./assets/docker/daemon.json", map[string]any{
+		"mirror": mirror,
+	})
 	if dErr != nil {
 		return nil, dErr
 	}
@@ -34,8 +44,25 @@ func Install(
 	if cfErr != nil {
 		return nil, cfErr
 	}
-	return remote.NewCommand(ctx, "remote-command-install-docker", &remote.CommandArgs{
+	installCmd, iErr := remote.NewCommand(ctx, "remote-command-install-docker", &remote.CommandArgs{
 		Create:     pulumi.StringPtr(createFn),
 		Connection: conn,
 	}, dependsOn)
+	if iErr != nil {
+		return nil, iErr
+	}
+
+	// the daemon configuration of existing servers is updated in place
+	configureFn, cErr := template.Render("./assets/docker/configure.sh", map[string]any{
+		"daemonJson": daemonJSON,
+	})
+	if cErr != nil {
+		return nil, cErr
+	}
+	return remote.NewCommand(ctx, "remote-command-configure-docker", &remote.CommandArgs{
+		Create:     pulumi.StringPtr(configureFn),
+		Update:     pulumi.StringPtr(configureFn),
+		Triggers:   pulumi.Array{pulumi.String(daemonJSON)},
+		Connection: conn,
+	}, dependsOn, pulumi.DependsOn([]pulumi.Resource{installCmd}))
 }
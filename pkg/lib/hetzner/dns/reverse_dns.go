@@ -3,8 +3,6 @@ package dns
 import (
 	"fmt"
 
-	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
-	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/pulumi/convert"
 	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
@@ -12,33 +10,31 @@ import (
 
 // CreateReverseDNSRecords creates reverse DNS records for the given IPv4 and IPv6 primary IPs.
 // ctx: Pulumi context.
+// name: Name suffix of the reverse DNS resources (e.g. the datacenter name).
 // ipv4: Primary IPv4 address.
 // ipv6: Primary IPv6 address.
 // ipv6Address: IPv6 address.
-// datacenter: Datacenter name.
-// mailConfig: Mail configuration for determining the mail server name.
+// hostname: The hostname the reverse DNS records point to.
 func CreateReverseDNSRecords(ctx *pulumi.Context,
+	name string,
 	ipv4 *hcloud.PrimaryIp,
 	ipv6 *hcloud.PrimaryIp,
 	ipv6Address pulumi.StringOutput,
-	datacenter string,
-	mailConfig *mailConf.Config,
+	hostname string,
 ) error {
-	mainServer := mail.Mailname(*mailConfig.Main.Name)
-
-	_, rdns4Err := hcloud.NewRdns(ctx, fmt.Sprintf("hcloud-rdns-ipv4-%s", datacenter), &hcloud.RdnsArgs{
+	_, rdns4Err := hcloud.NewRdns(ctx, fmt.Sprintf("hcloud-rdns-ipv4-%s", name), &hcloud.RdnsArgs{
 		PrimaryIpId: convert.IDToInt(ipv4.ID()),
 		IpAddress:   ipv4.IpAddress,
-		DnsPtr:      pulumi.String(mainServer),
+		DnsPtr:      pulumi.String(hostname),
 	})
 	if rdns4Err != nil {
 		return rdns4Err
 	}
 
-	_, rdns6Err := hcloud.NewRdns(ctx, fmt.Sprintf("hcloud-rdns-ipv6-%s", datacenter), &hcloud.RdnsArgs{
+	_, rdns6Err := hcloud.NewRdns(ctx, fmt.Sprintf("hcloud-rdns-ipv6-%s", name), &hcloud.RdnsArgs{
 		PrimaryIpId: convert.IDToInt(ipv6.ID()),
 		IpAddress:   ipv6Address,
-		DnsPtr:      pulumi.String(mainServer),
+		DnsPtr:      pulumi.String(hostname),
 	})
 	if rdns6Err != nil {
 		return rdns6Err
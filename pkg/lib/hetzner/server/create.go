@@ -20,6 +20,7 @@ import (
 	networkConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/network"
 	serverConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 	serverModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/server"
+	mailUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
 )
 
 // Create creates a new Hetzner server.
@@ -35,9 +36,6 @@ func Create(
 	networkConfig *networkConf.Config,
 	mailConfig *mail.Config,
 ) (*serverModel.Data, error) {
-	// location & datacenter
-	dc := location.ToDatacenter(serverConfig.Location)
-
 	// SSH Key
 	hetznerSSHKey, hErr := sshkey.Create(ctx, config.GlobalNameShort, &sshkey.CreateOptions{
 		Name:      fmt.Sprintf("%s-%s", config.GlobalName, config.Environment),
@@ -63,8 +61,40 @@ func Create(
 		return nil, fErr
 	}
 
+	return createServer(ctx, "", mailUtil.Mailname(*mailConfig.Main.Name), serverConfig, &serverModel.Data{
+		Network:    pulumi.String(*networkConfig.Name).ToStringOutput(),
+		NetworkID:  network,
+		SSHKeyID:   hetznerSSHKey.ID().ToStringOutput(),
+		FirewallID: convert.IDToInt(firewall.ID()),
+	}, true)
+}
+
+// createServer creates the primary IPs and the Hetzner server sharing the SSH key, network, and firewall.
+// ctx: Pulumi context.
+// suffix: Name suffix to distinguish additional nodes from the primary server (empty for the primary server).
+// hostname: The public hostname used for reverse DNS.
+// serverConfig: Configuration for the server.
+// shared: The shared SSH key, network, and firewall data.
+// backups: Whether Hetzner backups are enabled for the server.
+func createServer(
+	ctx *pulumi.Context,
+	suffix string,
+	hostname string,
+	serverConfig *serverConf.Config,
+	shared *serverModel.Data,
+	backups bool,
+) (*serverModel.Data, error) {
+	// location & datacenter
+	dc := location.ToDatacenter(serverConfig.Location)
+
 	// primary IPs
-	primaryIPv4, primaryIPv6, publicIPv6, pipErr := createIPAddresses(ctx, dc, *serverConfig.Location, mailConfig)
+	primaryIPv4, primaryIPv6, publicIPv6, pipErr := createIPAddresses(
+		ctx,
+		suffix,
+		dc,
+		*serverConfig.Location,
+		hostname,
+	)
 	if pipErr != nil {
 		return nil, pipErr
 	}
@@ -73,25 +103,26 @@ func Create(
 	enableIPv6 := false
 	server, sErr := server.Create(
 		ctx,
-		fmt.Sprintf("%s-%s", config.GlobalNameShort, *serverConfig.Location),
+		resourceName(config.GlobalNameShort, suffix, *serverConfig.Location),
 		&server.CreateOptions{
-			Hostname: pulumi.Sprintf(
-				"%s-%s-%s",
-				config.GlobalName,
-				config.Environment,
-				*serverConfig.Location,
+			Hostname: pulumi.String(
+				resourceName(
+					fmt.Sprintf("%s-%s", config.GlobalName, config.Environment),
+					suffix,
+					*serverConfig.Location,
+				),
 			),
 			ServerType:         pulumi.String(*serverConfig.Type),
 			Image:              pulumi.String("ubuntu-24.04"),
-			SSHKeys:            []pulumi.StringInput{hetznerSSHKey.ID().ToStringOutput()},
+			SSHKeys:            []pulumi.StringInput{shared.SSHKeyID},
 			Location:           pulumi.String(*serverConfig.Location),
-			NetworkID:          network,
+			NetworkID:          shared.NetworkID,
 			IPAddress:          pulumi.String(*serverConfig.IPv4),
 			PrimaryIPv4Address: primaryIPv4,
 			PrimaryIPv6Address: primaryIPv6,
 			EnableIPv6:         &enableIPv6,
-			Firewalls:          []pulumi.IntInput{convert.IDToInt(firewall.ID())},
-			Backups:            pulumi.Bool(true),
+			Firewalls:          []pulumi.IntInput{shared.FirewallID},
+			Backups:            pulumi.Bool(backups),
 			Protection:         true,
 			Labels:             config.CommonLabels(),
 			PublicSSH:          *serverConfig.PublicSSH,
@@ -107,29 +138,35 @@ func Create(
 	}
 	return &serverModel.Data{
 		Resource:    server.Resource,
+		ID:          convert.IDToInt(server.Resource.ID()),
 		Hostname:    server.Hostname,
 		PrivateIPv4: pulumi.String(*serverConfig.IPv4).ToStringOutput(),
 		PublicIPv4:  primaryIPv4.IpAddress,
 		PublicIPv6:  *publicIPv6,
 		SSHIPv4:     sshIP,
-		Network:     pulumi.String(*networkConfig.Name).ToStringOutput(),
+		Network:     shared.Network,
+		NetworkID:   shared.NetworkID,
+		SSHKeyID:    shared.SSHKeyID,
+		FirewallID:  shared.FirewallID,
 	}, nil
 }
 
 // createIPAddresses creates primary IPv4 and IPv6 addresses, sets up reverse DNS records, and returns the created IPs.
 // ctx: Pulumi context.
+// suffix: Name suffix to distinguish additional nodes from the primary server (empty for the primary server).
 // dc: Datacenter where the IPs will be created.
 // location: Location for the IPs, used for DNS setup.
-// mailConfig: Configuration for mail services, used for DNS setup.
+// hostname: The hostname the reverse DNS records point to.
 func createIPAddresses(
 	ctx *pulumi.Context,
+	suffix string,
 	dc string,
 	location string,
-	mailConfig *mail.Config,
+	hostname string,
 ) (*hcloud.PrimaryIp, *hcloud.PrimaryIp, *pulumi.StringOutput, error) {
 	// primary IPs
-	primaryIPv4, pv4Err := primaryip.Create(ctx, config.GlobalNameShort, &primaryip.CreateOptions{
-		Name:       fmt.Sprintf("%s-%s", config.GlobalName, config.Environment),
+	primaryIPv4, pv4Err := primaryip.Create(ctx, resourceName(config.GlobalNameShort, suffix), &primaryip.CreateOptions{
+		Name:       resourceName(fmt.Sprintf("%s-%s", config.GlobalName, config.Environment), suffix),
 		IPType:     "ipv4",
 		Datacenter: &dc,
 		Location:   location,
@@ -139,8 +176,8 @@ func createIPAddresses(
 	if pv4Err != nil {
 		return nil, nil, nil, pv4Err
 	}
-	primaryIPv6, pv6Err := primaryip.Create(ctx, config.GlobalNameShort, &primaryip.CreateOptions{
-		Name:       fmt.Sprintf("%s-%s", config.GlobalName, config.Environment),
+	primaryIPv6, pv6Err := primaryip.Create(ctx, resourceName(config.GlobalNameShort, suffix), &primaryip.CreateOptions{
+		Name:       resourceName(fmt.Sprintf("%s-%s", config.GlobalName, config.Environment), suffix),
 		IPType:     "ipv6",
 		Datacenter: &dc,
 		Location:   location,
@@ -153,10 +190,24 @@ func createIPAddresses(
 	publicIPv6 := pulumi.Sprintf("%s1", primaryIPv6.IpAddress)
 
 	// dns
-	dErr := dns.CreateReverseDNSRecords(ctx, primaryIPv4, primaryIPv6, publicIPv6, dc, mailConfig)
+	dErr := dns.CreateReverseDNSRecords(ctx, resourceName(dc, suffix), primaryIPv4, primaryIPv6, publicIPv6, hostname)
 	if dErr != nil {
 		return nil, nil, nil, dErr
 	}
 
 	return primaryIPv4, primaryIPv6, &publicIPv6, nil
 }
+
+// resourceName joins the given name, the optional node suffix, and additional name parts with dashes.
+// name: The base name.
+// suffix: The optional node suffix (omitted if empty).
+// parts: Additional name parts to append.
+func resourceName(name string, suffix string, parts ...string) string {
+	if suffix != "" {
+		name = fmt.Sprintf("%s-%s", name, suffix)
+	}
+	for _, part := range parts {
+		name = fmt.Sprintf("%s-%s", name, part)
+	}
+	return name
+}
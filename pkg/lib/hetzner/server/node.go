@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	serverConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	serverModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/server"
+)
+
+// CreateNode creates an additional Hetzner server sharing the SSH key, network, and firewall of the primary server.
+// ctx: Pulumi context.
+// name: The name of the node (used as a suffix for all resource names).
+// hostname: The public hostname of the node, used for reverse DNS.
+// primary: The data of the primary server.
+// serverConfig: Configuration for the node.
+func CreateNode(
+	ctx *pulumi.Context,
+	name string,
+	hostname string,
+	primary *serverModel.Data,
+	serverConfig *serverConf.Config,
+) (*serverModel.Data, error) {
+	return createServer(ctx, name, hostname, serverConfig, primary, false)
+}
@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+)
+
+// defaultRetention is the default number of snapshots kept per component.
+const defaultRetention = 3
+
+// triggerHashLength is the length of the trigger hash label (Hetzner label values are limited to 63 characters).
+const triggerHashLength = 32
+
+// Create takes a snapshot of a Hetzner server before a remote command of a component runs.
+// The snapshot is labelled with the hash of the command's triggers and replaced whenever they change.
+// Replaced snapshots are retained and deleted by the retention policy, keeping the newest ones.
+// ctx: Pulumi context.
+// component: The component the snapshot is taken for (e.g. 'mailcow').
+// serverID: The identifier of the server to take the snapshot of.
+// triggers: The triggers of the remote command.
+// serverConfig: Server configuration.
+// opts: Additional Pulumi resource options.
+func Create(
+	ctx *pulumi.Context,
+	component string,
+	serverID pulumi.IntInput,
+	triggers pulumi.Array,
+	serverConfig *server.Config,
+	opts ...pulumi.ResourceOption,
+) (*hcloud.Snapshot, error) {
+	triggerHash, _ := triggers.ToArrayOutput().ApplyT(func(values []any) string {
+		sum := sha256.Sum256(fmt.Appendf(nil, "%v", values))
+		return hex.EncodeToString(sum[:])[:triggerHashLength]
+	}).(pulumi.StringOutput)
+
+	labels := pulumi.StringMap{
+		"component":    pulumi.String(component),
+		"trigger-hash": triggerHash,
+	}
+	for key, value := range config.CommonLabels() {
+		labels[key] = pulumi.String(value)
+	}
+
+	snapshot, snErr := hcloud.NewSnapshot(ctx, fmt.Sprintf("hcloud-snapshot-%s", component), &hcloud.SnapshotArgs{
+		ServerId:    serverID,
+		Description: pulumi.Sprintf("%s-%s", Description(component), triggerHash),
+		Labels:      labels,
+	}, append(opts,
+		pulumi.ReplaceOnChanges([]string{"labels", "description"}),
+		pulumi.RetainOnDelete(true),
+	)...)
+	if snErr != nil {
+		return nil, snErr
+	}
+
+	retErr := cleanup(ctx, component, snapshot, defaults.GetOrDefault(serverConfig.SnapshotRetention, defaultRetention))
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	return snapshot, nil
+}
+
+// Description returns the description prefix of a component's snapshots in the Hetzner console.
+// component: The component the snapshots are taken for.
+func Description(component string) string {
+	return fmt.Sprintf("%s-%s-%s", config.GlobalName, config.Environment, component)
+}
+
+// cleanup deletes all but the newest snapshots of a component after a new snapshot has been taken.
+// The Hetzner API is accessed with the token in the HCLOUD_TOKEN environment variable.
+// ctx: Pulumi context.
+// component: The component the snapshots are taken for.
+// snapshot: The newest snapshot.
+// retention: The number of snapshots to keep.
+func cleanup(ctx *pulumi.Context, component string, snapshot *hcloud.Snapshot, retention int) error {
+	labels := config.CommonLabels()
+	script, sErr := template.Render("./assets/hetzner/snapshot-retention.sh.j2", map[string]any{
+		"component": component,
+		"retention": retention,
+		"selector": fmt.Sprintf(
+			"purpose=%s,environment=%s,component=%s",
+			labels["purpose"],
+			labels["environment"],
+			component,
+		),
+	})
+	if sErr != nil {
+		return sErr
+	}
+
+	_, cErr := local.NewCommand(ctx, fmt.Sprintf("local-command-snapshot-retention-%s", component), &local.CommandArgs{
+		Create:   pulumi.String(script),
+		Update:   pulumi.String(script),
+		Triggers: pulumi.Array{snapshot.ID(), pulumi.Int(retention)},
+	}, pulumi.DependsOn([]pulumi.Resource{snapshot}))
+	return cErr
+}
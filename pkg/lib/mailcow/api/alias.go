@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Alias is an alias in mailcow.
+type Alias struct {
+	// ID is the identifier of the alias.
+	ID int `json:"id"`
+	// Address is the address of the alias.
+	Address string `json:"address"`
+	// Goto is the comma-separated list of destination addresses.
+	Goto string `json:"goto"`
+}
+
+// AliasDomain is an alias domain in mailcow.
+type AliasDomain struct {
+	// AliasDomain is the name of the alias domain.
+	AliasDomain string `json:"alias_domain"`
+	// TargetDomain is the name of the target domain.
+	TargetDomain string `json:"target_domain"`
+}
+
+// ListAliases returns all aliases.
+// ctx: The context of the request.
+func (c *Client) ListAliases(ctx context.Context) ([]Alias, error) {
+	var aliases []Alias
+	if err := c.get(ctx, "get/alias/all", &aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// GetAlias returns the alias with the given address, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// address: The address of the alias.
+func (c *Client) GetAlias(ctx context.Context, address string) (*Alias, error) {
+	aliases, err := c.ListAliases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		if alias.Address == address {
+			return &alias, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateAlias creates an alias.
+// ctx: The context of the request.
+// address: The address of the alias.
+// gotoAddresses: The comma-separated list of destination addresses.
+// active: Whether the alias is active ('1') or not ('0').
+func (c *Client) CreateAlias(ctx context.Context, address string, gotoAddresses string, active string) error {
+	return c.post(ctx, "add/alias", map[string]any{
+		"address": address,
+		"goto":    gotoAddresses,
+		"active":  active,
+	})
+}
+
+// EditAlias edits an alias.
+// ctx: The context of the request.
+// id: The identifier of the alias.
+// gotoAddresses: The comma-separated list of destination addresses.
+// active: Whether the alias is active ('1') or not ('0').
+func (c *Client) EditAlias(ctx context.Context, id int, gotoAddresses string, active string) error {
+	return c.post(ctx, "edit/alias", map[string]any{
+		"items": []string{fmt.Sprintf("%d", id)},
+		"attr": map[string]any{
+			"goto":   gotoAddresses,
+			"active": active,
+		},
+	})
+}
+
+// ListAliasDomains returns all alias domains.
+// ctx: The context of the request.
+func (c *Client) ListAliasDomains(ctx context.Context) ([]AliasDomain, error) {
+	var aliasDomains []AliasDomain
+	if err := c.get(ctx, "get/alias-domain/all", &aliasDomains); err != nil {
+		return nil, err
+	}
+	return aliasDomains, nil
+}
+
+// GetAliasDomain returns the alias domain with the given name, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// name: The name of the alias domain.
+func (c *Client) GetAliasDomain(ctx context.Context, name string) (*AliasDomain, error) {
+	aliasDomains, err := c.ListAliasDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, aliasDomain := range aliasDomains {
+		if aliasDomain.AliasDomain == name {
+			return &aliasDomain, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateAliasDomain creates an alias domain.
+// ctx: The context of the request.
+// name: The name of the alias domain.
+// target: The name of the target domain.
+// active: Whether the alias domain is active ('1') or not ('0').
+func (c *Client) CreateAliasDomain(ctx context.Context, name string, target string, active string) error {
+	return c.post(ctx, "add/alias-domain", map[string]any{
+		"alias_domain":  name,
+		"target_domain": target,
+		"active":        active,
+	})
+}
+
+// EditAliasDomain edits an alias domain.
+// ctx: The context of the request.
+// name: The name of the alias domain.
+// target: The name of the target domain.
+// active: Whether the alias domain is active ('1') or not ('0').
+func (c *Client) EditAliasDomain(ctx context.Context, name string, target string, active string) error {
+	return c.post(ctx, "edit/alias-domain", map[string]any{
+		"items": []string{name},
+		"attr": map[string]any{
+			"target_domain": target,
+			"active":        active,
+		},
+	})
+}
+
+// DeleteAlias deletes an alias.
+// ctx: The context of the request.
+// id: The identifier of the alias.
+func (c *Client) DeleteAlias(ctx context.Context, id int) error {
+	return c.post(ctx, "delete/alias", []string{fmt.Sprintf("%d", id)})
+}
+
+// DeleteAliasDomain deletes an alias domain.
+// ctx: The context of the request.
+// name: The name of the alias domain.
+func (c *Client) DeleteAliasDomain(ctx context.Context, name string) error {
+	return c.post(ctx, "delete/alias-domain", []string{name})
+}
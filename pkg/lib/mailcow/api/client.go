@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// defaultTimeout is the default timeout of a single API request.
+const defaultTimeout = 30 * time.Second
+
+// defaultRetries is the default number of retries of a failed request.
+const defaultRetries = 5
+
+// defaultBackoff is the default initial backoff between retries; it doubles with every retry.
+const defaultBackoff = time.Second
+
+// Client is a client for the mailcow API.
+type Client struct {
+	// baseURL is the base URL of the mailcow API (e.g. 'https://mail.example.com/api/v1').
+	baseURL string
+	// apiKey is the API key used to authenticate against the mailcow API.
+	apiKey string
+	// httpClient is the HTTP client used for the requests.
+	httpClient *http.Client
+	// retries is the number of retries of a failed request.
+	retries int
+	// backoff is the initial backoff between retries.
+	backoff time.Duration
+}
+
+// Option configures the client.
+type Option func(*Client)
+
+// Response is a single message returned by mailcow for a change request.
+type Response struct {
+	// Type is the type of the message (e.g. 'success', 'danger', 'error').
+	Type string `json:"type"`
+	// Msg is the message; mailcow returns either a string or a list of strings.
+	Msg any `json:"msg"`
+}
+
+// WithHTTPClient sets the HTTP client used for the requests.
+// httpClient: The HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets the number of retries and the initial backoff between them.
+// retries: The number of retries of a failed request.
+// backoff: The initial backoff between retries; it doubles with every retry.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// NewClient creates a new mailcow API client.
+// baseURL: The base URL of the mailcow API (e.g. 'https://mail.example.com/api/v1').
+// apiKey: The API key used to authenticate against the mailcow API.
+// opts: Options to configure the client.
+func NewClient(baseURL string, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// get requests the given path and decodes the response into out.
+// ctx: The context of the request.
+// path: The API path (e.g. 'get/domain/all').
+// out: The value to decode the response into.
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// post sends the body to the given path and fails if mailcow reports an unsuccessful change.
+// ctx: The context of the request.
+// path: The API path (e.g. 'add/domain').
+// body: The request body.
+func (c *Client) post(ctx context.Context, path string, body any) error {
+	var responses []Response
+	if err := c.do(ctx, http.MethodPost, path, body, &responses); err != nil {
+		return err
+	}
+	for _, response := range responses {
+		if response.Type != "success" {
+			return &APIError{
+				Method:     http.MethodPost,
+				Path:       path,
+				StatusCode: http.StatusOK,
+				Type:       response.Type,
+				Message:    response.Msg,
+			}
+		}
+	}
+	return nil
+}
+
+// do executes the API request and retries it with an exponential backoff on transient errors.
+// ctx: The context of the request.
+// method: The HTTP method.
+// path: The API path.
+// body: The request body (optional).
+// out: The value to decode the response into (optional).
+func (c *Client) do(ctx context.Context, method string, path string, body any, out any) error {
+	var data []byte
+	if body != nil {
+		var mErr error
+		data, mErr = json.Marshal(body)
+		if mErr != nil {
+			return mErr
+		}
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.request(ctx, method, path, data, out)
+		if err == nil || attempt >= c.retries || !c.retryable(method, err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryable returns whether the failed request may be retried.
+// method: The HTTP method of the request.
+// err: The error of the request.
+func (c *Client) retryable(method string, err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.retryable(method)
+	}
+	// network errors are only retried for requests without side effects
+	return method == http.MethodGet
+}
+
+// request executes a single API request.
+// ctx: The context of the request.
+// method: The HTTP method.
+// path: The API path.
+// data: The encoded request body (optional).
+// out: The value to decode the response into (optional).
+func (c *Client) request(ctx context.Context, method string, path string, data []byte, out any) error {
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+
+	req, rErr := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.baseURL, path), reader)
+	if rErr != nil {
+		return rErr
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, dErr := c.httpClient.Do(req)
+	if dErr != nil {
+		return dErr
+	}
+	defer resp.Body.Close()
+
+	content, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return readErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(content)),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+	// mailcow returns an empty object instead of an empty list if there are no results
+	if string(bytes.TrimSpace(content)) == "{}" && reflect.Indirect(reflect.ValueOf(out)).Kind() == reflect.Slice {
+		return nil
+	}
+	return json.Unmarshal(content, out)
+}
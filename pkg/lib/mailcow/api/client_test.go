@@ -0,0 +1,198 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api/fake"
+)
+
+// flakyServer starts a server failing the first requests with the given status code.
+// t: The test.
+// failures: The number of failing requests.
+// status: The status code of the failing requests.
+// body: The body of the successful response.
+func flakyServer(t *testing.T, failures int32, status int, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func TestListEmptyObject(t *testing.T) {
+	server := fake.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+
+	domains, err := client.ListDomains(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, domains)
+
+	_, err = client.GetDomain(context.Background(), "example.com")
+	require.ErrorIs(t, err, api.ErrNotFound)
+
+	hosts, err := client.ListForwardingHosts(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+}
+
+func TestDecode(t *testing.T) {
+	server := fake.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	require.NoError(t, client.CreateAlias(ctx, "info@example.com", "admin@example.com", "1"))
+	require.NoError(t, client.CreateAlias(ctx, "abuse@example.com", "admin@example.com", "1"))
+
+	aliases, err := client.ListAliases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []api.Alias{
+		{ID: 1, Address: "info@example.com", Goto: "admin@example.com"},
+		{ID: 2, Address: "abuse@example.com", Goto: "admin@example.com"},
+	}, aliases)
+
+	alias, err := client.GetAlias(ctx, "abuse@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, alias.ID)
+}
+
+func TestUnauthorized(t *testing.T) {
+	server := fake.NewServer("key")
+	defer server.Close()
+	client := api.NewClient(server.BaseURL(), "invalid", api.WithRetries(0, 0))
+
+	_, err := client.ListDomains(context.Background())
+	require.ErrorIs(t, err, api.ErrUnauthorized)
+	require.NotErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestNotFound(t *testing.T) {
+	server, _ := flakyServer(t, 1, http.StatusNotFound, "")
+	client := api.NewClient(server.URL, "key", api.WithRetries(0, 0))
+
+	_, err := client.ListDomains(context.Background())
+	require.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestUnsuccessfulChange(t *testing.T) {
+	server := fake.NewServer("key")
+	defer server.Close()
+
+	err := server.Client().CreateMailbox(context.Background(), "admin", "example.com", "secret", &api.MailboxAttributes{
+		Name:   "Admin",
+		Active: "1",
+	})
+
+	var apiErr *api.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "add/mailbox", apiErr.Path)
+	assert.Equal(t, "danger", apiErr.Type)
+	assert.Empty(t, server.Mailboxes)
+}
+
+func TestInvalidResponse(t *testing.T) {
+	server, _ := flakyServer(t, 0, http.StatusOK, `{"domain_name":`)
+	client := api.NewClient(server.URL, "key", api.WithRetries(0, 0))
+
+	_, err := client.ListDomains(context.Background())
+	require.Error(t, err)
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		change   bool
+		attempts int32
+		success  bool
+	}{
+		{name: "read unavailable", status: http.StatusServiceUnavailable, attempts: 3, success: true},
+		{name: "read internal error", status: http.StatusInternalServerError, attempts: 3, success: true},
+		{name: "read rate limited", status: http.StatusTooManyRequests, attempts: 3, success: true},
+		{name: "read bad request", status: http.StatusBadRequest, attempts: 1},
+		{name: "change bad gateway", status: http.StatusBadGateway, change: true, attempts: 3, success: true},
+		{name: "change internal error", status: http.StatusInternalServerError, change: true, attempts: 1},
+		{name: "change rate limited", status: http.StatusTooManyRequests, change: true, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := "[]"
+			if tt.change {
+				body = `[{"type":"success","msg":"ok"}]`
+			}
+			server, attempts := flakyServer(t, 2, tt.status, body)
+			client := api.NewClient(server.URL, "key", api.WithRetries(5, time.Millisecond))
+
+			var err error
+			if tt.change {
+				err = client.DeleteDomain(context.Background(), "example.com")
+			} else {
+				_, err = client.ListDomains(context.Background())
+			}
+
+			assert.Equal(t, tt.attempts, attempts.Load())
+			if tt.success {
+				require.NoError(t, err)
+				return
+			}
+			var apiErr *api.APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+		})
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	server, attempts := flakyServer(t, 10, http.StatusServiceUnavailable, "[]")
+	client := api.NewClient(server.URL, "key", api.WithRetries(2, time.Millisecond))
+
+	_, err := client.ListDomains(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	client := api.NewClient(server.URL, "key", api.WithRetries(1, time.Millisecond))
+
+	_, err := client.ListDomains(context.Background())
+	require.Error(t, err)
+
+	err = client.DeleteDomain(context.Background(), "example.com")
+	require.Error(t, err)
+	var apiErr *api.APIError
+	assert.False(t, errors.As(err, &apiErr))
+}
+
+func TestRetryCanceled(t *testing.T) {
+	server, attempts := flakyServer(t, 10, http.StatusServiceUnavailable, "[]")
+	client := api.NewClient(server.URL, "key", api.WithRetries(5, time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ListDomains(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), attempts.Load())
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// DKIMKey is the DKIM key of a domain in mailcow.
+type DKIMKey struct {
+	// PublicKey is the public key.
+	PublicKey string `json:"pubkey"`
+	// Length is the key length in bits.
+	Length string `json:"length"`
+	// Selector is the DKIM selector.
+	Selector string `json:"dkim_selector"`
+	// TXT is the value of the DNS TXT record.
+	TXT string `json:"dkim_txt"`
+	// PrivateKey is the private key (only returned if requested).
+	PrivateKey string `json:"privkey"`
+}
+
+// GetDKIM returns the DKIM key of the domain, or ErrNotFound if the domain has no key.
+// ctx: The context of the request.
+// domain: The domain name.
+func (c *Client) GetDKIM(ctx context.Context, domain string) (*DKIMKey, error) {
+	var key DKIMKey
+	if err := c.get(ctx, fmt.Sprintf("get/dkim/%s", domain), &key); err != nil {
+		return nil, err
+	}
+	// mailcow returns an empty object for domains without a key
+	if key.PublicKey == "" {
+		return nil, ErrNotFound
+	}
+	return &key, nil
+}
+
+// CreateDKIM generates a DKIM key for the domains.
+// ctx: The context of the request.
+// domains: The domain names.
+// selector: The DKIM selector.
+// keySize: The key length in bits.
+func (c *Client) CreateDKIM(ctx context.Context, domains []string, selector string, keySize int) error {
+	return c.post(ctx, "add/dkim", map[string]any{
+		"domains":       domains,
+		"dkim_selector": selector,
+		"key_size":      keySize,
+	})
+}
+
+// ImportDKIM imports an existing DKIM private key for the domain.
+// ctx: The context of the request.
+// domain: The domain name.
+// selector: The DKIM selector.
+// keySize: The key length in bits.
+// privateKey: The private key in PEM format.
+func (c *Client) ImportDKIM(ctx context.Context, domain string, selector string, keySize int, privateKey string) error {
+	return c.post(ctx, "add/dkim_import", map[string]any{
+		"domain":           domain,
+		"dkim_selector":    selector,
+		"key_size":         keySize,
+		"private_key_file": privateKey,
+	})
+}
+
+// DeleteDKIM deletes the DKIM keys of the domains.
+// ctx: The context of the request.
+// domains: The domain names.
+func (c *Client) DeleteDKIM(ctx context.Context, domains []string) error {
+	return c.post(ctx, "delete/dkim", domains)
+}
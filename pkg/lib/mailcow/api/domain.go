@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Domain is a mail domain in mailcow.
+type Domain struct {
+	// Name is the domain name.
+	Name string `json:"domain_name"`
+	// Description is the description of the domain.
+	Description string `json:"description"`
+}
+
+// DomainAttributes are the attributes to create or edit a domain with.
+type DomainAttributes struct {
+	// Description is the description of the domain.
+	Description string `json:"description"`
+	// Mailboxes is the maximal number of mailboxes.
+	Mailboxes int `json:"mailboxes"`
+	// Aliases is the maximal number of aliases.
+	Aliases int `json:"aliases"`
+	// Quota is the total quota of the domain in MiB.
+	Quota int `json:"quota"`
+	// DefaultQuota is the default quota of a mailbox in MiB.
+	DefaultQuota int `json:"defquota"`
+	// MaxQuota is the maximal quota of a mailbox in MiB.
+	MaxQuota int `json:"maxquota"`
+	// RateLimitValue is the number of messages per rate limit frame (0 disables the rate limit).
+	RateLimitValue int `json:"rl_value"`
+	// RateLimitFrame is the rate limit frame ('s', 'm', 'h', 'd').
+	RateLimitFrame string `json:"rl_frame"`
+	// Active indicates if the domain is active ('1') or not ('0').
+	Active string `json:"active"`
+	// BackupMX indicates if mail of the domain is relayed ('1') instead of delivered to mailboxes.
+	BackupMX string `json:"backupmx,omitempty"`
+	// RelayAllRecipients indicates if mail to all recipients of a relayed domain is accepted ('1').
+	RelayAllRecipients string `json:"relay_all_recipients,omitempty"`
+	// RelayUnknownOnly indicates if only mail to recipients without a mailbox is relayed ('1').
+	RelayUnknownOnly string `json:"relay_unknown_only,omitempty"`
+}
+
+// ListDomains returns all domains.
+// ctx: The context of the request.
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+	if err := c.get(ctx, "get/domain/all", &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// GetDomain returns the domain with the given name, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// name: The domain name.
+func (c *Client) GetDomain(ctx context.Context, name string) (*Domain, error) {
+	domains, err := c.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		if domain.Name == name {
+			return &domain, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateDomain creates a domain.
+// ctx: The context of the request.
+// name: The domain name.
+// attributes: The attributes of the domain.
+func (c *Client) CreateDomain(ctx context.Context, name string, attributes *DomainAttributes) error {
+	return c.post(ctx, "add/domain", map[string]any{
+		"domain":               name,
+		"description":          attributes.Description,
+		"mailboxes":            attributes.Mailboxes,
+		"aliases":              attributes.Aliases,
+		"quota":                attributes.Quota,
+		"defquota":             attributes.DefaultQuota,
+		"maxquota":             attributes.MaxQuota,
+		"rl_value":             attributes.RateLimitValue,
+		"rl_frame":             attributes.RateLimitFrame,
+		"active":               attributes.Active,
+		"backupmx":             attributes.BackupMX,
+		"relay_all_recipients": attributes.RelayAllRecipients,
+		"relay_unknown_only":   attributes.RelayUnknownOnly,
+		"restart_sogo":         "1",
+	})
+}
+
+// EditDomain edits a domain.
+// ctx: The context of the request.
+// name: The domain name.
+// attributes: The attributes of the domain.
+func (c *Client) EditDomain(ctx context.Context, name string, attributes *DomainAttributes) error {
+	if err := c.post(ctx, "edit/domain", map[string]any{
+		"items": []string{name},
+		"attr":  attributes,
+	}); err != nil {
+		return err
+	}
+	return c.post(ctx, "edit/rl-domain", map[string]any{
+		"items": []string{name},
+		"attr": map[string]any{
+			"rl_value": fmt.Sprintf("%d", attributes.RateLimitValue),
+			"rl_frame": attributes.RateLimitFrame,
+		},
+	})
+}
+
+// DeleteDomain deletes a domain; mailcow refuses to delete domains which still contain mailboxes.
+// ctx: The context of the request.
+// name: The domain name.
+func (c *Client) DeleteDomain(ctx context.Context, name string) error {
+	return c.post(ctx, "delete/domain", []string{name})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotFound is returned if the requested object does not exist.
+var ErrNotFound = errors.New("mailcow object not found")
+
+// ErrUnauthorized is returned if the API key is invalid or the client's address is not allowed.
+var ErrUnauthorized = errors.New("mailcow API request unauthorized")
+
+// APIError is returned if the mailcow API responds with an error.
+type APIError struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the API path of the request.
+	Path string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Type is the message type returned by mailcow (e.g. 'danger', 'error').
+	Type string
+	// Message is the message returned by mailcow.
+	Message any
+}
+
+// Error returns the error message.
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("mailcow API %s %s failed: %s: %v", e.Method, e.Path, e.Type, e.Message)
+	}
+	return fmt.Sprintf("mailcow API %s %s returned status %d: %v", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// Is maps the error to ErrUnauthorized or ErrNotFound based on the status code.
+// target: The target error.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	default:
+		return false
+	}
+}
+
+// retryable returns whether the request may be retried.
+// A change request is only retried if it did not reach mailcow (gateway errors).
+// method: The HTTP method of the request.
+func (e *APIError) retryable(method string) bool {
+	switch e.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusTooManyRequests, http.StatusInternalServerError:
+		return method == http.MethodGet
+	default:
+		return false
+	}
+}
@@ -0,0 +1,389 @@
+// Package fake provides an in-memory fake of the mailcow API based on httptest for offline testing.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+)
+
+// Server is an in-memory fake of the mailcow API.
+type Server struct {
+	// Server is the underlying HTTP test server.
+	*httptest.Server
+
+	// apiKey is the API key accepted by the server.
+	apiKey string
+	// mu guards the state of the server.
+	mu sync.Mutex
+	// nextID is the next identifier of created objects.
+	nextID int
+
+	// Version is the mailcow version reported by the server.
+	Version string
+	// Domains are the domains by name.
+	Domains map[string]map[string]any
+	// Mailboxes are the mailboxes by address.
+	Mailboxes map[string]map[string]any
+	// Aliases are the aliases by identifier.
+	Aliases map[int]map[string]any
+	// AliasDomains are the alias domains by name.
+	AliasDomains map[string]map[string]any
+	// Relayhosts are the relayhosts by identifier.
+	Relayhosts map[int]map[string]any
+	// Transports are the transports by identifier.
+	Transports map[int]map[string]any
+	// ForwardingHosts are the forwarding hosts by address.
+	ForwardingHosts map[string]map[string]any
+	// RspamdSettings are the rspamd settings maps by identifier.
+	RspamdSettings map[int]map[string]any
+	// DKIM are the DKIM keys by domain.
+	DKIM map[string]api.DKIMKey
+	// Quarantine are the quarantined messages.
+	Quarantine []api.QuarantineItem
+	// Logs are the log entries by service.
+	Logs map[api.LogService][]api.LogEntry
+	// Requests are the paths of all received requests.
+	Requests []string
+}
+
+// NewServer starts a new fake mailcow API server accepting the given API key.
+// The server must be closed by the caller.
+// apiKey: The API key accepted by the server.
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		apiKey:          apiKey,
+		nextID:          1,
+		Version:         "2025-01",
+		Domains:         map[string]map[string]any{},
+		Mailboxes:       map[string]map[string]any{},
+		Aliases:         map[int]map[string]any{},
+		AliasDomains:    map[string]map[string]any{},
+		Relayhosts:      map[int]map[string]any{},
+		Transports:      map[int]map[string]any{},
+		ForwardingHosts: map[string]map[string]any{},
+		RspamdSettings:  map[int]map[string]any{},
+		DKIM:            map[string]api.DKIMKey{},
+		Logs:            map[api.LogService][]api.LogEntry{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// BaseURL returns the base URL of the fake API to create a client with.
+func (s *Server) BaseURL() string {
+	return fmt.Sprintf("%s/api/v1", s.URL)
+}
+
+// Client returns a client for the fake API without retries.
+func (s *Server) Client() *api.Client {
+	return api.NewClient(s.BaseURL(), s.apiKey, api.WithHTTPClient(s.Server.Client()), api.WithRetries(0, 0))
+}
+
+// handle handles a single API request.
+// w: The response writer.
+// r: The request.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/")
+	s.Requests = append(s.Requests, fmt.Sprintf("%s %s", r.Method, path))
+
+	if r.Header.Get("X-API-Key") != s.apiKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"type": "error", "msg": "authentication failed"})
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		s.handleGet(w, path)
+		return
+	}
+
+	var body any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"type": "error", "msg": err.Error()})
+		return
+	}
+	if err := s.handleChange(path, body); err != nil {
+		writeJSON(w, http.StatusOK, []api.Response{{Type: "danger", Msg: err.Error()}})
+		return
+	}
+	writeJSON(w, http.StatusOK, []api.Response{{Type: "success", Msg: path}})
+}
+
+// handleGet handles a read request.
+// w: The response writer.
+// path: The API path.
+func (s *Server) handleGet(w http.ResponseWriter, path string) {
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "get/status/version":
+		writeJSON(w, http.StatusOK, api.Version{Version: s.Version})
+	case path == "get/domain/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.Domains)))
+	case len(parts) == 3 && parts[0] == "get" && parts[1] == "domain":
+		writeJSON(w, http.StatusOK, objectOrEmpty(s.Domains[parts[2]]))
+	case path == "get/mailbox/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.Mailboxes)))
+	case path == "get/alias/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.Aliases)))
+	case path == "get/alias-domain/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.AliasDomains)))
+	case path == "get/relayhost/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.Relayhosts)))
+	case path == "get/transport/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.Transports)))
+	case path == "get/fwdhost/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.ForwardingHosts)))
+	case path == "get/rsetting/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(valuesByKey(s.RspamdSettings)))
+	case path == "get/quarantine/all":
+		writeJSON(w, http.StatusOK, listOrEmpty(s.Quarantine))
+	case len(parts) == 3 && parts[0] == "get" && parts[1] == "dkim":
+		key, ok := s.DKIM[parts[2]]
+		if !ok {
+			writeJSON(w, http.StatusOK, map[string]any{})
+			return
+		}
+		writeJSON(w, http.StatusOK, key)
+	case len(parts) == 4 && parts[0] == "get" && parts[1] == "logs":
+		count, _ := strconv.Atoi(parts[3])
+		entries := s.Logs[api.LogService(parts[2])]
+		writeJSON(w, http.StatusOK, entries[:min(count, len(entries))])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"type": "error", "msg": "route not found"})
+	}
+}
+
+// handleChange handles a change request.
+// path: The API path.
+// body: The decoded request body.
+//
+//nolint:gocognit,funlen // the fake keeps all routes in one place
+func (s *Server) handleChange(path string, body any) error {
+	attrs, _ := body.(map[string]any)
+
+	switch path {
+	case "add/domain":
+		name := fmt.Sprint(attrs["domain"])
+		if _, ok := s.Domains[name]; ok {
+			return fmt.Errorf("domain %s already exists", name)
+		}
+		attrs["domain_name"] = name
+		s.Domains[name] = attrs
+	case "edit/domain", "edit/rl-domain":
+		return s.editByName(s.Domains, attrs)
+	case "delete/domain":
+		return deleteByName(s.Domains, body)
+	case "add/mailbox":
+		address := fmt.Sprintf("%v@%v", attrs["local_part"], attrs["domain"])
+		if _, ok := s.Domains[fmt.Sprint(attrs["domain"])]; !ok {
+			return fmt.Errorf("domain %v does not exist", attrs["domain"])
+		}
+		if _, ok := s.Mailboxes[address]; ok {
+			return fmt.Errorf("mailbox %s already exists", address)
+		}
+		attrs["username"] = address
+		s.Mailboxes[address] = attrs
+	case "edit/mailbox":
+		return s.editByName(s.Mailboxes, attrs)
+	case "delete/mailbox":
+		return deleteByName(s.Mailboxes, body)
+	case "add/alias":
+		attrs["id"] = s.newID()
+		s.Aliases[attrs["id"].(int)] = attrs
+	case "edit/alias":
+		return s.editByID(s.Aliases, attrs)
+	case "delete/alias":
+		return deleteByID(s.Aliases, body)
+	case "add/alias-domain":
+		name := fmt.Sprint(attrs["alias_domain"])
+		s.AliasDomains[name] = attrs
+	case "edit/alias-domain":
+		return s.editByName(s.AliasDomains, attrs)
+	case "delete/alias-domain":
+		return deleteByName(s.AliasDomains, body)
+	case "add/relayhost":
+		attrs["id"] = s.newID()
+		s.Relayhosts[attrs["id"].(int)] = attrs
+	case "edit/relayhost":
+		return s.editByID(s.Relayhosts, attrs)
+	case "add/transport":
+		attrs["id"] = s.newID()
+		s.Transports[attrs["id"].(int)] = attrs
+	case "edit/transport":
+		return s.editByID(s.Transports, attrs)
+	case "delete/transport":
+		return deleteByID(s.Transports, body)
+	case "add/fwdhost":
+		host := fmt.Sprint(attrs["hostname"])
+		s.ForwardingHosts[host] = map[string]any{"host": host, "source": host}
+	case "delete/fwdhost":
+		return deleteByName(s.ForwardingHosts, body)
+	case "add/rsetting":
+		attrs["id"] = s.newID()
+		s.RspamdSettings[attrs["id"].(int)] = attrs
+	case "edit/rsetting":
+		return s.editByID(s.RspamdSettings, attrs)
+	case "delete/rsetting":
+		return deleteByID(s.RspamdSettings, body)
+	case "add/dkim", "add/dkim_import":
+		domains, _ := attrs["domains"].([]any)
+		if domain, ok := attrs["domain"]; ok {
+			domains = append(domains, domain)
+		}
+		for _, domain := range domains {
+			s.DKIM[fmt.Sprint(domain)] = api.DKIMKey{
+				PublicKey: fmt.Sprintf("fake-public-key-%v", domain),
+				Length:    fmt.Sprint(attrs["key_size"]),
+				Selector:  fmt.Sprint(attrs["dkim_selector"]),
+				TXT:       fmt.Sprintf("v=DKIM1;k=rsa;t=s;s=email;p=fake-public-key-%v", domain),
+			}
+		}
+	case "delete/dkim":
+		for _, domain := range items(body) {
+			delete(s.DKIM, domain)
+		}
+	case "delete/qitem":
+		ids := items(body)
+		s.Quarantine = slices.DeleteFunc(s.Quarantine, func(item api.QuarantineItem) bool {
+			return slices.Contains(ids, strconv.Itoa(item.ID))
+		})
+	default:
+		return fmt.Errorf("route %s not implemented", path)
+	}
+	return nil
+}
+
+// newID returns a new object identifier.
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// editByName merges the attributes into the objects referenced by name.
+// objects: The objects by name.
+// body: The request body with 'items' and 'attr'.
+func (s *Server) editByName(objects map[string]map[string]any, body map[string]any) error {
+	attr, _ := body["attr"].(map[string]any)
+	for _, name := range items(body["items"]) {
+		object, ok := objects[name]
+		if !ok {
+			return fmt.Errorf("object %s does not exist", name)
+		}
+		for key, value := range attr {
+			object[key] = value
+		}
+	}
+	return nil
+}
+
+// editByID merges the attributes into the objects referenced by identifier.
+// objects: The objects by identifier.
+// body: The request body with 'items' and 'attr'.
+func (s *Server) editByID(objects map[int]map[string]any, body map[string]any) error {
+	attr, _ := body["attr"].(map[string]any)
+	for _, item := range items(body["items"]) {
+		id, _ := strconv.Atoi(item)
+		object, ok := objects[id]
+		if !ok {
+			return fmt.Errorf("object %d does not exist", id)
+		}
+		for key, value := range attr {
+			object[key] = value
+		}
+	}
+	return nil
+}
+
+// deleteByName deletes the objects referenced by name.
+// objects: The objects by name.
+// body: The request body (list of names).
+func deleteByName(objects map[string]map[string]any, body any) error {
+	for _, name := range items(body) {
+		if _, ok := objects[name]; !ok {
+			return fmt.Errorf("object %s does not exist", name)
+		}
+		delete(objects, name)
+	}
+	return nil
+}
+
+// deleteByID deletes the objects referenced by identifier.
+// objects: The objects by identifier.
+// body: The request body (list of identifiers).
+func deleteByID(objects map[int]map[string]any, body any) error {
+	for _, item := range items(body) {
+		id, _ := strconv.Atoi(item)
+		if _, ok := objects[id]; !ok {
+			return fmt.Errorf("object %d does not exist", id)
+		}
+		delete(objects, id)
+	}
+	return nil
+}
+
+// items converts a decoded JSON list into a list of strings.
+// value: The decoded JSON list.
+func items(value any) []string {
+	list, _ := value.([]any)
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		result = append(result, fmt.Sprint(item))
+	}
+	return result
+}
+
+// valuesByKey returns the values of the map ordered by their keys.
+// objects: The objects.
+func valuesByKey[K int | string](objects map[K]map[string]any) []map[string]any {
+	keys := make([]K, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	values := make([]map[string]any, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, objects[key])
+	}
+	return values
+}
+
+// listOrEmpty returns the values or an empty object, as mailcow does for lists without results.
+// values: The values.
+func listOrEmpty[T any](values []T) any {
+	if len(values) == 0 {
+		return map[string]any{}
+	}
+	return values
+}
+
+// objectOrEmpty returns the object or an empty object, as mailcow does for unknown objects.
+// object: The object.
+func objectOrEmpty(object map[string]any) map[string]any {
+	if object == nil {
+		return map[string]any{}
+	}
+	return object
+}
+
+// writeJSON writes the value as JSON response.
+// w: The response writer.
+// status: The HTTP status code.
+// value: The value to write.
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
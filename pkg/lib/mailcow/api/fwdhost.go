@@ -0,0 +1,54 @@
+package api
+
+import "context"
+
+// ForwardingHost is a forwarding host in mailcow, whose mail is not rejected by IP based checks.
+type ForwardingHost struct {
+	// Host is the address of the forwarding host.
+	Host string `json:"host"`
+	// Source is the hostname or address the forwarding host was added with.
+	Source string `json:"source"`
+}
+
+// ListForwardingHosts returns all forwarding hosts.
+// ctx: The context of the request.
+func (c *Client) ListForwardingHosts(ctx context.Context) ([]ForwardingHost, error) {
+	var hosts []ForwardingHost
+	if err := c.get(ctx, "get/fwdhost/all", &hosts); err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// GetForwardingHost returns the forwarding host with the given address, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// host: The address of the forwarding host.
+func (c *Client) GetForwardingHost(ctx context.Context, host string) (*ForwardingHost, error) {
+	hosts, err := c.ListForwardingHosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, forwardingHost := range hosts {
+		if forwardingHost.Host == host {
+			return &forwardingHost, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateForwardingHost adds a forwarding host; its mail is still filtered for spam.
+// ctx: The context of the request.
+// host: The address of the forwarding host.
+func (c *Client) CreateForwardingHost(ctx context.Context, host string) error {
+	return c.post(ctx, "add/fwdhost", map[string]any{
+		"hostname":    host,
+		"filter_spam": "1",
+	})
+}
+
+// DeleteForwardingHost deletes a forwarding host.
+// ctx: The context of the request.
+// host: The address of the forwarding host.
+func (c *Client) DeleteForwardingHost(ctx context.Context, host string) error {
+	return c.post(ctx, "delete/fwdhost", []string{host})
+}
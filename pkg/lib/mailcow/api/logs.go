@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// LogEntry is a single log entry of a mailcow service.
+type LogEntry struct {
+	// Time is the time of the entry (Unix timestamp as string).
+	Time string `json:"time"`
+	// Priority is the syslog priority of the entry.
+	Priority string `json:"priority"`
+	// Program is the program which wrote the entry.
+	Program string `json:"program"`
+	// Message is the log message.
+	Message string `json:"message"`
+}
+
+// LogService is a mailcow service providing logs through the API.
+type LogService string
+
+// mailcow services providing logs through the API.
+const (
+	LogServicePostfix LogService = "postfix"
+	LogServiceDovecot LogService = "dovecot"
+	LogServiceSOGo    LogService = "sogo"
+	LogServiceACME    LogService = "acme"
+	LogServiceAPI     LogService = "api"
+	LogServiceWatch   LogService = "watchdog"
+)
+
+// GetLogs returns the latest log entries of a service.
+// ctx: The context of the request.
+// service: The mailcow service.
+// count: The maximal number of entries.
+func (c *Client) GetLogs(ctx context.Context, service LogService, count int) ([]LogEntry, error) {
+	var entries []LogEntry
+	if err := c.get(ctx, fmt.Sprintf("get/logs/%s/%d", service, count), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
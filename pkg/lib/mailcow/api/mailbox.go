@@ -0,0 +1,108 @@
+package api
+
+import "context"
+
+// Mailbox is a mailbox in mailcow.
+type Mailbox struct {
+	// Username is the address of the mailbox.
+	Username string `json:"username"`
+	// Name is the full name of the mailbox owner.
+	Name string `json:"name"`
+	// Domain is the domain of the mailbox.
+	Domain string `json:"domain"`
+}
+
+// MailboxAttributes are the attributes to create or edit a mailbox with.
+type MailboxAttributes struct {
+	// Name is the full name of the mailbox owner.
+	Name string `json:"name"`
+	// Quota is the quota of the mailbox in MiB (0 keeps the domain's default quota).
+	Quota int `json:"quota,omitempty"`
+	// Active indicates if the mailbox is active ('1') or not ('0').
+	Active string `json:"active"`
+}
+
+// ListMailboxes returns all mailboxes.
+// ctx: The context of the request.
+func (c *Client) ListMailboxes(ctx context.Context) ([]Mailbox, error) {
+	var mailboxes []Mailbox
+	if err := c.get(ctx, "get/mailbox/all", &mailboxes); err != nil {
+		return nil, err
+	}
+	return mailboxes, nil
+}
+
+// GetMailbox returns the mailbox with the given address, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// username: The address of the mailbox.
+func (c *Client) GetMailbox(ctx context.Context, username string) (*Mailbox, error) {
+	mailboxes, err := c.ListMailboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, mailbox := range mailboxes {
+		if mailbox.Username == username {
+			return &mailbox, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateMailbox creates a mailbox.
+// ctx: The context of the request.
+// localPart: The local part of the address.
+// domain: The domain of the address.
+// password: The initial password of the mailbox.
+// attributes: The attributes of the mailbox.
+func (c *Client) CreateMailbox(
+	ctx context.Context,
+	localPart string,
+	domain string,
+	password string,
+	attributes *MailboxAttributes,
+) error {
+	body := map[string]any{
+		"local_part": localPart,
+		"domain":     domain,
+		"name":       attributes.Name,
+		"password":   password,
+		"password2":  password,
+		"active":     attributes.Active,
+	}
+	if attributes.Quota > 0 {
+		body["quota"] = attributes.Quota
+	}
+	return c.post(ctx, "add/mailbox", body)
+}
+
+// EditMailbox edits a mailbox; the password is left untouched.
+// ctx: The context of the request.
+// username: The address of the mailbox.
+// attributes: The attributes of the mailbox.
+func (c *Client) EditMailbox(ctx context.Context, username string, attributes *MailboxAttributes) error {
+	return c.post(ctx, "edit/mailbox", map[string]any{
+		"items": []string{username},
+		"attr":  attributes,
+	})
+}
+
+// DeleteMailbox deletes a mailbox including all its messages.
+// ctx: The context of the request.
+// username: The address of the mailbox.
+func (c *Client) DeleteMailbox(ctx context.Context, username string) error {
+	return c.post(ctx, "delete/mailbox", []string{username})
+}
+
+// SetMailboxPassword sets the password of a mailbox.
+// ctx: The context of the request.
+// username: The address of the mailbox.
+// password: The password of the mailbox.
+func (c *Client) SetMailboxPassword(ctx context.Context, username string, password string) error {
+	return c.post(ctx, "edit/mailbox", map[string]any{
+		"items": []string{username},
+		"attr": map[string]string{
+			"password":  password,
+			"password2": password,
+		},
+	})
+}
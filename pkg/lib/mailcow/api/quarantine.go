@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// QuarantineItem is a quarantined message in mailcow.
+type QuarantineItem struct {
+	// ID is the identifier of the quarantined message.
+	ID int `json:"id"`
+	// QID is the queue identifier of the message.
+	QID string `json:"qid"`
+	// Subject is the subject of the message.
+	Subject string `json:"subject"`
+	// Sender is the envelope sender of the message.
+	Sender string `json:"sender"`
+	// Recipient is the recipient of the message.
+	Recipient string `json:"rcpt"`
+	// Score is the rspamd score of the message.
+	Score float64 `json:"score"`
+	// Action is the rspamd action taken for the message.
+	Action string `json:"action"`
+	// Created is the time the message was quarantined (Unix timestamp).
+	Created int64 `json:"created"`
+}
+
+// ListQuarantine returns all quarantined messages.
+// ctx: The context of the request.
+func (c *Client) ListQuarantine(ctx context.Context) ([]QuarantineItem, error) {
+	var items []QuarantineItem
+	if err := c.get(ctx, "get/quarantine/all", &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// DeleteQuarantine deletes quarantined messages.
+// ctx: The context of the request.
+// ids: The identifiers of the quarantined messages.
+func (c *Client) DeleteQuarantine(ctx context.Context, ids []int) error {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, fmt.Sprintf("%d", id))
+	}
+	return c.post(ctx, "delete/qitem", items)
+}
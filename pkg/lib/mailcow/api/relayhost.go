@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Relayhost is a sender-dependent transport in mailcow.
+type Relayhost struct {
+	// ID is the identifier of the relayhost.
+	ID int `json:"id"`
+	// Hostname is the hostname of the relayhost ('[host]:port').
+	Hostname string `json:"hostname"`
+}
+
+// ListRelayhosts returns all relayhosts.
+// ctx: The context of the request.
+func (c *Client) ListRelayhosts(ctx context.Context) ([]Relayhost, error) {
+	var relayhosts []Relayhost
+	if err := c.get(ctx, "get/relayhost/all", &relayhosts); err != nil {
+		return nil, err
+	}
+	return relayhosts, nil
+}
+
+// GetRelayhost returns the relayhost with the given hostname, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// hostname: The hostname of the relayhost ('[host]:port').
+func (c *Client) GetRelayhost(ctx context.Context, hostname string) (*Relayhost, error) {
+	relayhosts, err := c.ListRelayhosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, relayhost := range relayhosts {
+		if relayhost.Hostname == hostname {
+			return &relayhost, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// SetDomainRelayhost assigns the relayhost to the domain (0 sends directly).
+// ctx: The context of the request.
+// domain: The domain name.
+// id: The identifier of the relayhost.
+func (c *Client) SetDomainRelayhost(ctx context.Context, domain string, id int) error {
+	return c.post(ctx, "edit/domain", map[string]any{
+		"items": []string{domain},
+		"attr": map[string]any{
+			"relayhost": fmt.Sprintf("%d", id),
+		},
+	})
+}
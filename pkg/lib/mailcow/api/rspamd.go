@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// RspamdSetting is an rspamd settings map in mailcow.
+type RspamdSetting struct {
+	// ID is the identifier of the settings map.
+	ID int `json:"id"`
+	// Description is the description of the settings map.
+	Description string `json:"desc"`
+	// Content is the rspamd settings content.
+	Content string `json:"content"`
+}
+
+// ListRspamdSettings returns all rspamd settings maps.
+// ctx: The context of the request.
+func (c *Client) ListRspamdSettings(ctx context.Context) ([]RspamdSetting, error) {
+	var settings []RspamdSetting
+	if err := c.get(ctx, "get/rsetting/all", &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetRspamdSetting returns the rspamd settings map with the given description, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// description: The description of the settings map.
+func (c *Client) GetRspamdSetting(ctx context.Context, description string) (*RspamdSetting, error) {
+	settings, err := c.ListRspamdSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		if setting.Description == description {
+			return &setting, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateRspamdSetting creates an rspamd settings map.
+// ctx: The context of the request.
+// description: The description of the settings map.
+// content: The rspamd settings content.
+func (c *Client) CreateRspamdSetting(ctx context.Context, description string, content string) error {
+	return c.post(ctx, "add/rsetting", map[string]any{
+		"desc":    description,
+		"content": content,
+		"active":  "1",
+	})
+}
+
+// EditRspamdSetting edits an rspamd settings map.
+// ctx: The context of the request.
+// id: The identifier of the settings map.
+// description: The description of the settings map.
+// content: The rspamd settings content.
+func (c *Client) EditRspamdSetting(ctx context.Context, id int, description string, content string) error {
+	return c.post(ctx, "edit/rsetting", map[string]any{
+		"items": []string{fmt.Sprintf("%d", id)},
+		"attr": map[string]any{
+			"desc":    description,
+			"content": content,
+			"active":  "1",
+		},
+	})
+}
+
+// DeleteRspamdSetting deletes an rspamd settings map.
+// ctx: The context of the request.
+// id: The identifier of the settings map.
+func (c *Client) DeleteRspamdSetting(ctx context.Context, id int) error {
+	return c.post(ctx, "delete/rsetting", []string{fmt.Sprintf("%d", id)})
+}
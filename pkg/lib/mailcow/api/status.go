@@ -0,0 +1,19 @@
+package api
+
+import "context"
+
+// Version is the version information of mailcow.
+type Version struct {
+	// Version is the mailcow version (e.g. '2025-01a').
+	Version string `json:"version"`
+}
+
+// GetVersion returns the version of mailcow.
+// ctx: The context of the request.
+func (c *Client) GetVersion(ctx context.Context) (*Version, error) {
+	var version Version
+	if err := c.get(ctx, "get/status/version", &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
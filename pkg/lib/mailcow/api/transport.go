@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Transport is a transport map entry in mailcow.
+type Transport struct {
+	// ID is the identifier of the transport.
+	ID int `json:"id"`
+	// Destination is the destination domain or address of the transport.
+	Destination string `json:"destination"`
+	// Nexthop is the next hop of the transport (e.g. '[host]:port').
+	Nexthop string `json:"nexthop"`
+	// Username is the SASL username of the next hop.
+	Username string `json:"username"`
+}
+
+// TransportAttributes are the attributes to create or edit a transport with.
+type TransportAttributes struct {
+	// Destination is the destination domain or address of the transport.
+	Destination string `json:"destination"`
+	// Nexthop is the next hop of the transport (e.g. '[host]:port').
+	Nexthop string `json:"nexthop"`
+	// Username is the SASL username of the next hop.
+	Username string `json:"username"`
+	// Password is the SASL password of the next hop.
+	Password string `json:"password"`
+	// Active indicates if the transport is active ('1') or not ('0').
+	Active string `json:"active"`
+}
+
+// ListTransports returns all transport map entries.
+// ctx: The context of the request.
+func (c *Client) ListTransports(ctx context.Context) ([]Transport, error) {
+	var transports []Transport
+	if err := c.get(ctx, "get/transport/all", &transports); err != nil {
+		return nil, err
+	}
+	return transports, nil
+}
+
+// GetTransport returns the transport of the destination, or ErrNotFound if it does not exist.
+// ctx: The context of the request.
+// destination: The destination domain or address of the transport.
+func (c *Client) GetTransport(ctx context.Context, destination string) (*Transport, error) {
+	transports, err := c.ListTransports(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, transport := range transports {
+		if transport.Destination == destination {
+			return &transport, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateTransport creates a transport map entry.
+// ctx: The context of the request.
+// attributes: The attributes of the transport.
+func (c *Client) CreateTransport(ctx context.Context, attributes *TransportAttributes) error {
+	return c.post(ctx, "add/transport", attributes)
+}
+
+// EditTransport edits a transport map entry.
+// ctx: The context of the request.
+// id: The identifier of the transport.
+// attributes: The attributes of the transport.
+func (c *Client) EditTransport(ctx context.Context, id int, attributes *TransportAttributes) error {
+	return c.post(ctx, "edit/transport", map[string]any{
+		"items": []string{fmt.Sprintf("%d", id)},
+		"attr":  attributes,
+	})
+}
+
+// DeleteTransport deletes a transport map entry.
+// ctx: The context of the request.
+// id: The identifier of the transport.
+func (c *Client) DeleteTransport(ctx context.Context, id int) error {
+	return c.post(ctx, "delete/transport", []string{fmt.Sprintf("%d", id)})
+}
@@ -0,0 +1,52 @@
+package mailcow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/google/dns/record"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/dkim"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+)
+
+// createARCKeys creates the ARC keys of all signing domains and publishes them as DNS records.
+// It returns the private keys by domain, or nil if ARC is disabled.
+// ctx: Pulumi context.
+// mailConfig: Mail configuration.
+func createARCKeys(ctx *pulumi.Context, mailConfig *mailConf.Config) (map[string]pulumi.StringOutput, error) {
+	if !mail.ARCEnabled(mailConfig) {
+		return nil, nil
+	}
+
+	selector := mail.ARCSelector(mailConfig)
+	keys := map[string]pulumi.StringOutput{}
+	for _, domain := range append([]*dns.DomainConfig{mailConfig.Main}, mailConfig.Additional...) {
+		key, kErr := dkim.CreateKey(
+			ctx,
+			fmt.Sprintf("arc-%s", strings.ReplaceAll(*domain.Name, ".", "-")),
+			fmt.Sprintf("mailcow-arc-%s", *domain.Name),
+		)
+		if kErr != nil {
+			return nil, kErr
+		}
+
+		_, rErr := record.Create(ctx, &record.CreateOptions{
+			Domain:     fmt.Sprintf("%s._domainkey.%s", selector, *domain.Name),
+			ZoneID:     pulumi.String(*domain.ZoneID),
+			RecordType: recordTXT,
+			Records:    pulumi.StringArray{dkim.TXTRecord(key.PublicKey)},
+			Project:    domain.Project,
+		})
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		keys[*domain.Name] = key.PrivateKey
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,92 @@
+package mailcow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+)
+
+// createCheckTable renders a Postfix PCRE lookup table from the given rules,
+// uploads it to the remote server, and validates it with Postfix before activating it.
+// It returns a Pulumi Output representing the validation task and the hash of the lookup table.
+// ctx: Pulumi context.
+// conn: SSH connection arguments.
+// name: The name of the lookup table (without the '.pcre' extension).
+// rules: The check rules of the lookup table.
+// installTask: The installation task output to depend on.
+// opts: Additional Pulumi resource options.
+func createCheckTable(ctx *pulumi.Context,
+	conn *remote.ConnectionArgs,
+	name string,
+	rules []*mailConf.CheckRule,
+	installTask pulumi.Output,
+	opts ...pulumi.ResourceOption,
+) (pulumi.Output, pulumi.StringOutput, error) {
+	values := []map[string]string{}
+	for _, rule := range mail.FilterCheckRules(rules, config.Environment) {
+		values = append(values, map[string]string{
+			"pattern": *rule.Pattern,
+			"action":  *rule.Action,
+			"comment": defaults.GetOrDefault(rule.Comment, ""),
+		})
+	}
+
+	table, rErr := template.Render("./assets/mailcow/config/checks.pcre.j2", map[string]any{
+		"rules": values,
+	})
+	if rErr != nil {
+		return nil, pulumi.StringOutput{}, rErr
+	}
+	validateFn, vErr := template.Render("./assets/mailcow/validate-checks.sh.j2", map[string]any{
+		"name": name,
+	})
+	if vErr != nil {
+		return nil, pulumi.StringOutput{}, vErr
+	}
+
+	outputFile := fmt.Sprintf("./outputs/mailcow_%s.pcre", name)
+	resourceName := strings.ReplaceAll(name, "_", "-")
+
+	tableHash := file.WritePulumi(outputFile, pulumi.String(table)).
+		ApplyT(func(_ string) string {
+			hash, _ := file.Hash(outputFile)
+			return *hash
+		}).(pulumi.StringOutput)
+	tableValidation := pulumi.All(tableHash, installTask).ApplyT(func(args []any) pulumi.ResourceOrInvokeOption {
+		hash, _ := args[0].(string)
+		installer, _ := args[1].(pulumi.ResourceOption)
+		cmd, _ := remote.NewCopyToRemote(
+			ctx,
+			fmt.Sprintf("remote-copy-mailcow-postfix-%s", resourceName),
+			&remote.CopyToRemoteArgs{
+				Source:     pulumi.NewFileAsset(outputFile),
+				RemotePath: pulumi.Sprintf("/opt/mailcow/data/conf/postfix/%s.pcre.new", name),
+				Triggers:   pulumi.Array{pulumi.String(hash)},
+				Connection: conn,
+			},
+			append(opts, installer)...)
+		// the table's content is part of the triggers to show the rule changes in the preview
+		validation, _ := remote.NewCommand(
+			ctx,
+			fmt.Sprintf("remote-command-mailcow-postfix-validate-%s", resourceName),
+			&remote.CommandArgs{
+				Create:     pulumi.String(validateFn),
+				Update:     pulumi.String(validateFn),
+				Triggers:   pulumi.Array{pulumi.String(hash), pulumi.String(table)},
+				Connection: conn,
+			},
+			append(opts, installer, pulumi.DependsOn([]pulumi.Resource{cmd}))...)
+		return pulumi.DependsOn([]pulumi.Resource{validation})
+	})
+
+	return tableValidation, tableHash, nil
+}
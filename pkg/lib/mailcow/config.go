@@ -6,9 +6,13 @@ import (
 
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
 	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	ntfyConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/ntfy"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 	mcModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/mailcow"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/file"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	fileUtil "github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 )
@@ -22,6 +26,9 @@ import (
 // secrets: Mailcow secrets needed for configuration.
 // mailConfig: Mail configuration.
 // dnsConfig: DNS configuration.
+// serverConfig: Server configuration.
+// ntfyConfig: Ntfy configuration.
+// watchdogUser: The Ntfy user of the watchdog (optional).
 // opts: Additional Pulumi resource options.
 func createConfig(ctx *pulumi.Context,
 	conn *remote.ConnectionArgs,
@@ -30,9 +37,13 @@ func createConfig(ctx *pulumi.Context,
 	secrets *mcModel.Secrets,
 	mailConfig *mailConf.Config,
 	dnsConfig *dns.Config,
+	serverConfig *server.Config,
+	ntfyConfig *ntfyConf.Config,
+	watchdogUser *ntfyModel.User,
 	opts ...pulumi.ResourceOption,
 ) (pulumi.Output, pulumi.StringOutput) {
-	configFile, _ := pulumi.All(secrets.DBUserPassword, secrets.DBRootPassword, secrets.RedisPassword, secrets.APIKeyReadWrite, secrets.APIKeyRead, ipv4Address, ipv6Address).ApplyT(func(args []any) string {
+	webhook, webhookBody := watchdogWebhook(ntfyConfig, watchdogUser)
+	configFile, _ := pulumi.All(secrets.DBUserPassword, secrets.DBRootPassword, secrets.RedisPassword, secrets.APIKeyReadWrite, secrets.APIKeyRead, ipv4Address, ipv6Address, webhook).ApplyT(func(args []any) string {
 		userPassword, _ := args[0].(string)
 		rootPassword, _ := args[1].(string)
 		redisPassword, _ := args[2].(string)
@@ -40,6 +51,7 @@ func createConfig(ctx *pulumi.Context,
 		apiKeyRead, _ := args[4].(string)
 		ipv4, _ := args[5].(string)
 		ipv6, _ := args[6].(string)
+		webhookURL, _ := args[7].(string)
 
 		dc, _ := template.Render("./assets/mailcow/config/mailcow.conf.j2", map[string]any{
 			"mailname": mail.Mailname(*mailConfig.Main.Name),
@@ -63,6 +75,11 @@ func createConfig(ctx *pulumi.Context,
 			"acme": map[string]string{
 				"email": *dnsConfig.Email,
 			},
+			"settings": mail.MailcowSettings(mailConfig, defaults.GetOrDefault(serverConfig.Type, "")),
+			"watchdog": map[string]string{
+				"webhook":     webhookURL,
+				"webhookBody": webhookBody,
+			},
 		})
 		return dc
 	}).(pulumi.StringOutput)
@@ -1,45 +1,62 @@
 package mailcow
 
 import (
-	"os"
-	"strings"
+	"fmt"
 
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
-	"github.com/stretchr/testify/assert/yaml"
 
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/hetzner/snapshot"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object/resource"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
 	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	ntfyConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/ntfy"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 	mcModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/mailcow"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 )
 
 // Install Mailcow on the remote server via SSH and create necessary resources.
+// It returns the snapshot taken before the installation, the passwords of the managed mailboxes,
+// the connection to the mailcow API for objects managed by other components, and the image inventory.
 // ctx: Pulumi context.
 // ipv4Address: The public IPv4 address of the server.
 // ipv6Address: The public IPv6 address of the server.
+// serverID: The identifier of the server.
 // sshIPv4: The IPv4 address of the server to connect to via SSH.
 // privateKeyPem: The private key in PEM format to use for SSH authentication.
 // secrets: Mailcow secrets needed for installation.
 // mailConfig: Mail configuration.
 // dnsConfig: DNS configuration.
+// serverConfig: Server configuration.
+// ntfyConfig: Ntfy configuration.
+// watchdogUser: The Ntfy user of the watchdog (optional).
+// ntfyTask: The Ntfy installation task output provisioning the watchdog user.
 // dependsOn: List of Pulumi resources that this installation depends on.
 //
 //nolint:funlen // Function is long but clear in its purpose.
 func Install(ctx *pulumi.Context,
 	ipv4Address pulumi.StringOutput,
 	ipv6Address pulumi.StringOutput,
+	serverID pulumi.IntOutput,
 	sshIPv4 pulumi.StringOutput,
 	privateKeyPem pulumi.StringOutput,
 	secrets *mcModel.Secrets,
 	mailConfig *mailConf.Config,
 	dnsConfig *dns.Config,
+	serverConfig *server.Config,
+	ntfyConfig *ntfyConf.Config,
+	watchdogUser *ntfyModel.User,
+	ntfyTask pulumi.Output,
 	dependsOn pulumi.ResourceOrInvokeOption,
-) error {
+) (*hcloud.Snapshot, pulumi.MapOutput, *resource.Connection, pulumi.MapOutput, error) {
 	conn := &remote.ConnectionArgs{
 		Host:       sshIPv4,
 		PrivateKey: privateKeyPem,
@@ -50,13 +67,14 @@ func Install(ctx *pulumi.Context,
 
 	opts, prepErr := install.Prepare(ctx, "mailcow", conn, opts...)
 	if prepErr != nil {
-		return prepErr
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, prepErr
 	}
 
 	dockerCompose, _ := secrets.APIKeyRead.ApplyT(func(key string) string {
 		dc, _ := template.Render("./assets/mailcow/docker-compose.override.yml.j2", map[string]any{
-			"mailname": mail.Mailname(*mailConfig.Main.Name),
-			"apiKey":   key,
+			"mailname":  mail.Mailname(*mailConfig.Main.Name),
+			"apiKey":    key,
+			"retention": mail.RetentionJobs(mailConfig),
 		})
 		return dc
 	}).(pulumi.StringOutput)
@@ -65,10 +83,21 @@ func Install(ctx *pulumi.Context,
 		"mailcow",
 		dockerCompose,
 		true,
+		serverConfig,
 		conn,
 		opts...)
 	if dcErr != nil {
-		return dcErr
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, dcErr
+	}
+
+	opts, verifyImagesHash, viErr := install.VerifyImages(
+		ctx,
+		"mailcow",
+		serverConfig,
+		conn,
+		opts...)
+	if viErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, viErr
 	}
 
 	configFileCopy, configFileHash := createConfig(
@@ -79,51 +108,65 @@ func Install(ctx *pulumi.Context,
 		secrets,
 		mailConfig,
 		dnsConfig,
+		serverConfig,
+		ntfyConfig,
+		watchdogUser,
 		opts...,
 	)
 
-	_, cronErr := install.Cron(ctx, "mailcow", conn, opts...)
+	_, cronErr := install.Cron(ctx, "mailcow", nil, conn, opts...)
 	if cronErr != nil {
-		return cronErr
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, cronErr
 	}
 
 	opts, systemdServiceHash, shErr := install.SystemDService(ctx, "mailcow", conn, opts...)
 	if shErr != nil {
-		return shErr
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, shErr
 	}
 
-	mailcowVersion, _ := dockerComposeHash.ApplyT(func(_ string) string {
-		data, rErr := os.ReadFile("./outputs/mailcow_docker-compose.override.yml")
-		if rErr != nil {
-			return ""
-		}
-
-		s := strings.ReplaceAll(string(data), "#", "")
-		var parsed map[string]any
-		if pErr := yaml.Unmarshal([]byte(s), &parsed); pErr != nil {
-			return ""
-		}
-
-		v, ok := parsed["version"].(string)
-		if !ok {
-			return ""
-		}
-		return v
-	}).(pulumi.StringOutput)
+	opts, healthcheckHash, hcErr := install.HealthcheckScript(ctx, "mailcow", conn, opts...)
+	if hcErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, hcErr
+	}
+
+	mailname := mail.Mailname(*mailConfig.Main.Name)
+	healthcheck := install.HealthcheckCommand("mailcow", fmt.Sprintf("https://%s/", mailname), mailname)
+
+	mailcowVersion := mail.MailcowVersion(mailConfig)
+	installTriggers := pulumi.Array{
+		pulumi.String(*systemdServiceHash),
+		pulumi.String(*healthcheckHash),
+		dockerComposeHash,
+		configFileHash,
+		verifyImagesHash,
+		pulumi.String(mailcowVersion),
+	}
+
+	// a server snapshot is taken before every change of the installation to allow a fast rollback
+	installSnapshot, snErr := snapshot.Create(ctx, "mailcow", serverID, installTriggers, serverConfig, opts...)
+	if snErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, snErr
+	}
 
 	//nolint:godox // TODO is required
 	// TODO: restore doesn't work automated - https://github.com/mailcow/mailcow-dockerized/pull/5934
-	installFn, _ := mailcowVersion.ApplyT(func(version string) string {
-		ic, _ := template.Render("./assets/mailcow/install.sh.j2", map[string]any{
-			"version": version,
-			"bucket": map[string]string{
-				"id":   config.BackupBucketID,
-				"path": config.BackupBucketPath,
-			},
-			"dkimSignHeaders": strings.Join(mailConfig.DkimSignHeaders, ":"),
-		})
-		return ic
-	}).(pulumi.StringOutput)
+	installFn, _ := pulumi.All(installSnapshot.ID(), installSnapshot.Description).
+		ApplyT(func(args []any) (string, error) {
+			id, _ := args[0].(pulumi.ID)
+			description, _ := args[1].(*string)
+			return template.Render("./assets/mailcow/install.sh.j2", map[string]any{
+				"version":     mailcowVersion,
+				"healthcheck": healthcheck,
+				"snapshot": map[string]string{
+					"id":          string(id),
+					"description": defaults.GetOrDefault(description, snapshot.Description("mailcow")),
+				},
+				"bucket": map[string]string{
+					"id":   config.BackupBucketID,
+					"path": config.BackupBucketPath,
+				},
+			})
+		}).(pulumi.StringOutput)
 	installFileHash := file.WritePulumi("./outputs/mailcow_install.sh", installFn).
 		ApplyT(func(_ string) string {
 			hash, _ := file.Hash("./outputs/mailcow_install.sh")
@@ -152,21 +195,59 @@ func Install(ctx *pulumi.Context,
 				ctx,
 				"remote-command-install-mailcow",
 				&remote.CommandArgs{
-					Create: pulumi.Sprintf("bash /opt/mailcow/install.sh"),
-					Update: pulumi.Sprintf("bash /opt/mailcow/install.sh"),
-					Triggers: pulumi.Array{
-						pulumi.String(*systemdServiceHash),
-						dockerComposeHash,
-						configFileHash,
-						mailcowVersion,
-					},
+					Create:     pulumi.Sprintf("bash /opt/mailcow/install.sh"),
+					Update:     pulumi.Sprintf("bash /opt/mailcow/install.sh"),
+					Triggers:   installTriggers,
 					Connection: conn,
 				},
-				append(opts, configCopy, installCopy, dockerCopy)...)
+				append(opts, configCopy, installCopy, dockerCopy, pulumi.DependsOn([]pulumi.Resource{installSnapshot}))...)
 			return pulumi.DependsOn([]pulumi.Resource{cmd})
 		}).(pulumi.AnyOutput)
 
-	postinstall(ctx, conn, installTask, opts...)
+	arcKeys, arcErr := createARCKeys(ctx, mailConfig)
+	if arcErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, arcErr
+	}
+
+	_, rsErr := configureRspamd(ctx, conn, mailConfig, arcKeys, installTask, opts...)
+	if rsErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, rsErr
+	}
+
+	postinstallTask, piErr := postinstall(ctx, conn, installTask, mailConfig, *healthcheckHash, opts...)
+	if piErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, piErr
+	}
+
+	testWatchdogNotifications(
+		ctx,
+		conn,
+		mailConfig,
+		ntfyConfig,
+		watchdogUser,
+		postinstallTask,
+		ntfyTask,
+		opts...,
+	)
+
+	smarthostTask, smErr := configureSmarthosts(ctx, conn, secrets.APIKeyReadWrite, mailConfig, postinstallTask, opts...)
+	if smErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, smErr
+	}
+
+	mailboxPasswords, mailcowAPI, obErr := manageObjects(
+		ctx,
+		conn,
+		secrets.APIKeyReadWrite,
+		mailConfig,
+		serverConfig,
+		smarthostTask,
+		opts...)
+	if obErr != nil {
+		return nil, pulumi.MapOutput{}, nil, pulumi.MapOutput{}, obErr
+	}
+
+	images := install.Images("./outputs/mailcow_docker-compose.override.yml", dockerComposeHash)
 
-	return nil
+	return installSnapshot, mailboxPasswords, mailcowAPI, images, nil
 }
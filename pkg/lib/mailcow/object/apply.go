@@ -0,0 +1,225 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+)
+
+// Apply applies the lifecycle action to a mailcow object.
+// Creating an existing object adopts it; deleting a missing object succeeds.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The object.
+// password: The password of a mailbox (ignored for other kinds).
+func Apply(ctx context.Context, client *api.Client, action Action, obj *Object, password string) error {
+	switch obj.Kind {
+	case KindDomain:
+		return applyDomain(ctx, client, action, obj)
+	case KindAliasDomain:
+		return applyAliasDomain(ctx, client, action, obj)
+	case KindMailbox:
+		return applyMailbox(ctx, client, action, obj, password)
+	case KindAlias:
+		return applyAlias(ctx, client, action, obj)
+	case KindTransport:
+		return applyTransport(ctx, client, action, obj)
+	case KindForwardingHost:
+		return applyForwardingHost(ctx, client, action, obj)
+	default:
+		return fmt.Errorf("unknown mailcow object kind %q", obj.Kind)
+	}
+}
+
+// applyDomain applies the lifecycle action to a domain and assigns its relayhost.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The domain.
+func applyDomain(ctx context.Context, client *api.Client, action Action, obj *Object) error {
+	_, gErr := client.GetDomain(ctx, obj.Name)
+	if gErr != nil && !errors.Is(gErr, api.ErrNotFound) {
+		return gErr
+	}
+	exists := gErr == nil
+
+	switch {
+	case action == ActionDelete:
+		if !exists {
+			return nil
+		}
+		return client.DeleteDomain(ctx, obj.Name)
+	case !exists:
+		attributes := obj.Domain
+		if attributes == nil {
+			attributes = DefaultDomainAttributes()
+		}
+		if err := client.CreateDomain(ctx, obj.Name, attributes); err != nil {
+			return err
+		}
+	case obj.Domain != nil:
+		if err := client.EditDomain(ctx, obj.Name, obj.Domain); err != nil {
+			return err
+		}
+	}
+
+	if obj.Relayhost == nil {
+		return nil
+	}
+	id := 0
+	if *obj.Relayhost != "" {
+		relayhost, rErr := client.GetRelayhost(ctx, *obj.Relayhost)
+		if rErr != nil {
+			return fmt.Errorf("relayhost %s of domain %s: %w", *obj.Relayhost, obj.Name, rErr)
+		}
+		id = relayhost.ID
+	}
+	return client.SetDomainRelayhost(ctx, obj.Name, id)
+}
+
+// applyAliasDomain applies the lifecycle action to an alias domain.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The alias domain.
+func applyAliasDomain(ctx context.Context, client *api.Client, action Action, obj *Object) error {
+	_, gErr := client.GetAliasDomain(ctx, obj.Name)
+	if gErr != nil && !errors.Is(gErr, api.ErrNotFound) {
+		return gErr
+	}
+	exists := gErr == nil
+
+	switch {
+	case action == ActionDelete:
+		if !exists {
+			return nil
+		}
+		return client.DeleteAliasDomain(ctx, obj.Name)
+	case !exists:
+		return client.CreateAliasDomain(ctx, obj.Name, obj.Target, obj.Active)
+	default:
+		return client.EditAliasDomain(ctx, obj.Name, obj.Target, obj.Active)
+	}
+}
+
+// applyMailbox applies the lifecycle action to a mailbox.
+// The password is set when the mailbox is created, and on every change if it is enforced;
+// adopting an existing mailbox keeps the password of its owner.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The mailbox.
+// password: The password of the mailbox.
+func applyMailbox(ctx context.Context, client *api.Client, action Action, obj *Object, password string) error {
+	_, gErr := client.GetMailbox(ctx, obj.Name)
+	if gErr != nil && !errors.Is(gErr, api.ErrNotFound) {
+		return gErr
+	}
+	exists := gErr == nil
+
+	switch {
+	case action == ActionDelete:
+		if !exists {
+			return nil
+		}
+		return client.DeleteMailbox(ctx, obj.Name)
+	case !exists:
+		localPart, domain, found := strings.Cut(obj.Name, "@")
+		if !found {
+			return fmt.Errorf("%s is not a valid e-mail address", obj.Name)
+		}
+		return client.CreateMailbox(ctx, localPart, domain, password, obj.Mailbox)
+	}
+
+	if err := client.EditMailbox(ctx, obj.Name, obj.Mailbox); err != nil {
+		return err
+	}
+	if obj.EnforcePassword {
+		return client.SetMailboxPassword(ctx, obj.Name, password)
+	}
+	return nil
+}
+
+// applyAlias applies the lifecycle action to an alias.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The alias.
+func applyAlias(ctx context.Context, client *api.Client, action Action, obj *Object) error {
+	existing, gErr := client.GetAlias(ctx, obj.Name)
+	if gErr != nil && !errors.Is(gErr, api.ErrNotFound) {
+		return gErr
+	}
+	exists := gErr == nil
+
+	switch {
+	case action == ActionDelete:
+		if !exists {
+			return nil
+		}
+		return client.DeleteAlias(ctx, existing.ID)
+	case !exists:
+		return client.CreateAlias(ctx, obj.Name, obj.Target, obj.Active)
+	default:
+		return client.EditAlias(ctx, existing.ID, obj.Target, obj.Active)
+	}
+}
+
+// applyTransport applies the lifecycle action to a transport map entry.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The transport.
+func applyTransport(ctx context.Context, client *api.Client, action Action, obj *Object) error {
+	existing, gErr := client.GetTransport(ctx, obj.Name)
+	if gErr != nil && !errors.Is(gErr, api.ErrNotFound) {
+		return gErr
+	}
+	exists := gErr == nil
+
+	attributes := &api.TransportAttributes{
+		Destination: obj.Name,
+		Nexthop:     obj.Target,
+		Active:      obj.Active,
+	}
+	switch {
+	case action == ActionDelete:
+		if !exists {
+			return nil
+		}
+		return client.DeleteTransport(ctx, existing.ID)
+	case !exists:
+		return client.CreateTransport(ctx, attributes)
+	default:
+		return client.EditTransport(ctx, existing.ID, attributes)
+	}
+}
+
+// applyForwardingHost applies the lifecycle action to a forwarding host; a forwarding host has no attributes to update.
+// ctx: The context of the requests.
+// client: The mailcow API client.
+// action: The lifecycle action.
+// obj: The forwarding host.
+func applyForwardingHost(ctx context.Context, client *api.Client, action Action, obj *Object) error {
+	_, gErr := client.GetForwardingHost(ctx, obj.Name)
+	if gErr != nil && !errors.Is(gErr, api.ErrNotFound) {
+		return gErr
+	}
+	exists := gErr == nil
+
+	switch {
+	case action == ActionDelete:
+		if !exists {
+			return nil
+		}
+		return client.DeleteForwardingHost(ctx, obj.Name)
+	case !exists:
+		return client.CreateForwardingHost(ctx, obj.Name)
+	default:
+		return nil
+	}
+}
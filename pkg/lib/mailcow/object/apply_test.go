@@ -0,0 +1,200 @@
+package object_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api/fake"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object"
+)
+
+// newServer starts a fake mailcow API server closed with the test.
+// t: The test.
+func newServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	server := fake.NewServer("key")
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestApplyDomain(t *testing.T) {
+	server := newServer(t)
+	client := server.Client()
+	ctx := context.Background()
+	server.Relayhosts[7] = map[string]any{"id": 7, "hostname": "[smtp.example.net]:587"}
+
+	relayhost := "[smtp.example.net]:587"
+	domain := &object.Object{Kind: object.KindDomain, Name: "example.com", Relayhost: &relayhost}
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, domain, ""))
+	require.Contains(t, server.Domains, "example.com")
+	assert.InDelta(t, 10, server.Domains["example.com"]["mailboxes"], 0)
+	assert.Equal(t, "7", server.Domains["example.com"]["relayhost"])
+
+	direct := ""
+	domain.Relayhost = &direct
+	domain.Domain = &api.DomainAttributes{Description: "Example", Active: "1"}
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, domain, ""))
+	assert.Equal(t, "Example", server.Domains["example.com"]["description"])
+	assert.Equal(t, "0", server.Domains["example.com"]["relayhost"])
+
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, domain, ""))
+	assert.Empty(t, server.Domains)
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, domain, ""))
+}
+
+func TestApplyDomainAdopt(t *testing.T) {
+	server := newServer(t)
+	server.Domains["example.com"] = map[string]any{"domain_name": "example.com", "description": "Existing"}
+
+	domain := &object.Object{Kind: object.KindDomain, Name: "example.com"}
+	require.NoError(t, object.Apply(context.Background(), server.Client(), object.ActionCreate, domain, ""))
+	assert.Equal(t, "Existing", server.Domains["example.com"]["description"])
+	assert.NotContains(t, server.Requests, "POST add/domain")
+}
+
+func TestApplyDomainUnknownRelayhost(t *testing.T) {
+	server := newServer(t)
+
+	relayhost := "[smtp.example.net]:587"
+	domain := &object.Object{Kind: object.KindDomain, Name: "example.com", Relayhost: &relayhost}
+	err := object.Apply(context.Background(), server.Client(), object.ActionCreate, domain, "")
+	require.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestApplyMailbox(t *testing.T) {
+	server := newServer(t)
+	client := server.Client()
+	ctx := context.Background()
+	server.Domains["example.com"] = map[string]any{"domain_name": "example.com"}
+
+	mailbox := &object.Object{
+		Kind:    object.KindMailbox,
+		Name:    "admin@example.com",
+		Mailbox: &api.MailboxAttributes{Name: "Admin", Active: "1"},
+	}
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, mailbox, "initial"))
+	assert.Equal(t, "initial", server.Mailboxes["admin@example.com"]["password"])
+
+	mailbox.Mailbox.Name = "Administrator"
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, mailbox, "changed"))
+	assert.Equal(t, "Administrator", server.Mailboxes["admin@example.com"]["name"])
+	assert.Equal(t, "initial", server.Mailboxes["admin@example.com"]["password"])
+
+	mailbox.EnforcePassword = true
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, mailbox, "changed"))
+	assert.Equal(t, "changed", server.Mailboxes["admin@example.com"]["password"])
+
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, mailbox, ""))
+	assert.Empty(t, server.Mailboxes)
+}
+
+func TestApplyMailboxAdopt(t *testing.T) {
+	server := newServer(t)
+	server.Mailboxes["admin@example.com"] = map[string]any{"username": "admin@example.com", "password": "owner"}
+
+	mailbox := &object.Object{
+		Kind:    object.KindMailbox,
+		Name:    "admin@example.com",
+		Mailbox: &api.MailboxAttributes{Name: "Admin", Active: "1"},
+	}
+	require.NoError(t, object.Apply(context.Background(), server.Client(), object.ActionCreate, mailbox, "initial"))
+	assert.Equal(t, "owner", server.Mailboxes["admin@example.com"]["password"])
+	assert.Equal(t, "Admin", server.Mailboxes["admin@example.com"]["name"])
+}
+
+func TestApplyMailboxInvalidAddress(t *testing.T) {
+	server := newServer(t)
+
+	mailbox := &object.Object{Kind: object.KindMailbox, Name: "admin", Mailbox: &api.MailboxAttributes{}}
+	require.Error(t, object.Apply(context.Background(), server.Client(), object.ActionCreate, mailbox, ""))
+}
+
+func TestApplyAlias(t *testing.T) {
+	server := newServer(t)
+	client := server.Client()
+	ctx := context.Background()
+
+	alias := &object.Object{Kind: object.KindAlias, Name: "info@example.com", Target: "admin@example.com", Active: "1"}
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, alias, ""))
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, alias, ""))
+	require.Len(t, server.Aliases, 1)
+
+	alias.Target = "postmaster@example.com"
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, alias, ""))
+	assert.Equal(t, "postmaster@example.com", server.Aliases[1]["goto"])
+
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, alias, ""))
+	assert.Empty(t, server.Aliases)
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, alias, ""))
+}
+
+func TestApplyAliasDomain(t *testing.T) {
+	server := newServer(t)
+	client := server.Client()
+	ctx := context.Background()
+
+	aliasDomain := &object.Object{
+		Kind:   object.KindAliasDomain,
+		Name:   "example.net",
+		Target: "example.com",
+		Active: "1",
+	}
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, aliasDomain, ""))
+	assert.Equal(t, "example.com", server.AliasDomains["example.net"]["target_domain"])
+
+	aliasDomain.Active = "0"
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, aliasDomain, ""))
+	assert.Equal(t, "0", server.AliasDomains["example.net"]["active"])
+
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, aliasDomain, ""))
+	assert.Empty(t, server.AliasDomains)
+}
+
+func TestApplyTransport(t *testing.T) {
+	server := newServer(t)
+	client := server.Client()
+	ctx := context.Background()
+
+	transport := &object.Object{
+		Kind:   object.KindTransport,
+		Name:   "example.org",
+		Target: "[mx.example.org]:25",
+		Active: "1",
+	}
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, transport, ""))
+	require.Len(t, server.Transports, 1)
+
+	transport.Target = "[mx2.example.org]:25"
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, transport, ""))
+	assert.Equal(t, "[mx2.example.org]:25", server.Transports[1]["nexthop"])
+
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, transport, ""))
+	assert.Empty(t, server.Transports)
+}
+
+func TestApplyForwardingHost(t *testing.T) {
+	server := newServer(t)
+	client := server.Client()
+	ctx := context.Background()
+
+	host := &object.Object{Kind: object.KindForwardingHost, Name: "192.0.2.1"}
+	require.NoError(t, object.Apply(ctx, client, object.ActionCreate, host, ""))
+	require.NoError(t, object.Apply(ctx, client, object.ActionUpdate, host, ""))
+	assert.Contains(t, server.ForwardingHosts, "192.0.2.1")
+
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, host, ""))
+	assert.Empty(t, server.ForwardingHosts)
+	require.NoError(t, object.Apply(ctx, client, object.ActionDelete, host, ""))
+}
+
+func TestApplyUnknownKind(t *testing.T) {
+	server := newServer(t)
+
+	unknown := &object.Object{Kind: "unknown", Name: "example"}
+	require.Error(t, object.Apply(context.Background(), server.Client(), object.ActionCreate, unknown, ""))
+}
@@ -0,0 +1,87 @@
+package object
+
+import "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+
+// default domain attributes of mailcow.
+const (
+	defaultDomainMailboxes    = 10
+	defaultDomainAliases      = 400
+	defaultDomainQuota        = 10240
+	defaultDomainMailboxQuota = 3072
+	defaultDomainRateLimit    = "s"
+)
+
+// Kind is the kind of a mailcow object.
+type Kind string
+
+// kinds of mailcow objects.
+const (
+	// KindDomain is a mail or relay domain.
+	KindDomain Kind = "domain"
+	// KindAliasDomain is an alias domain.
+	KindAliasDomain Kind = "alias-domain"
+	// KindMailbox is a mailbox.
+	KindMailbox Kind = "mailbox"
+	// KindAlias is an alias.
+	KindAlias Kind = "alias"
+	// KindTransport is a transport map entry.
+	KindTransport Kind = "transport"
+	// KindForwardingHost is a forwarding host.
+	KindForwardingHost Kind = "forwarding-host"
+)
+
+// Action is the lifecycle action applied to a mailcow object.
+type Action string
+
+// lifecycle actions of mailcow objects.
+const (
+	// ActionCreate creates the object, or adopts it if it already exists.
+	ActionCreate Action = "create"
+	// ActionUpdate updates the attributes of the object.
+	ActionUpdate Action = "update"
+	// ActionDelete deletes the object.
+	ActionDelete Action = "delete"
+)
+
+// Object is a mailcow object managed as a Pulumi resource.
+type Object struct {
+	// Kind is the kind of the object.
+	Kind Kind `json:"kind"`
+	// Name identifies the object (domain name, alias domain, address, transport destination, or host address).
+	Name string `json:"name"`
+	// Domain are the attributes of a domain; existing domains without attributes are adopted as they are.
+	Domain *api.DomainAttributes `json:"domain,omitempty"`
+	// Relayhost is the hostname of the relayhost of a domain (empty sends directly).
+	Relayhost *string `json:"relayhost,omitempty"`
+	// Mailbox are the attributes of a mailbox.
+	Mailbox *api.MailboxAttributes `json:"mailbox,omitempty"`
+	// EnforcePassword sets the password of a mailbox on every change, not only when it is created.
+	EnforcePassword bool `json:"enforcePassword,omitempty"`
+	// Target is the target domain of an alias domain, the destinations of an alias, or the next hop of a transport.
+	Target string `json:"target,omitempty"`
+	// Active indicates if an alias domain, alias, or transport is active ('1') or not ('0').
+	Active string `json:"active,omitempty"`
+}
+
+// Request is the request of the helper applying a lifecycle action to a mailcow object, read from its stdin.
+type Request struct {
+	// APIKey is the read-write mailcow API key.
+	APIKey string `json:"apiKey"`
+	// Object is the object.
+	Object *Object `json:"object"`
+	// Password is the password of a mailbox (optional).
+	Password string `json:"password,omitempty"`
+}
+
+// DefaultDomainAttributes returns mailcow's default attributes of a domain.
+func DefaultDomainAttributes() *api.DomainAttributes {
+	return &api.DomainAttributes{
+		Mailboxes:      defaultDomainMailboxes,
+		Aliases:        defaultDomainAliases,
+		Quota:          defaultDomainQuota,
+		DefaultQuota:   defaultDomainMailboxQuota,
+		MaxQuota:       defaultDomainQuota,
+		RateLimitFrame: defaultDomainRateLimit,
+		Active:         "1",
+	}
+}
@@ -0,0 +1,65 @@
+package resource
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+)
+
+// helperSource is the package of the helper applying the lifecycle actions, relative to the project directory.
+const helperSource = "./cmd/mailcow-object"
+
+// helperPath is the path of the helper on the mailcow server.
+const helperPath = "/usr/local/bin/mailcow-object"
+
+// InstallHelper builds the helper applying the lifecycle actions to the mailcow objects once per deployment
+// for the architecture of the server, and copies it to the server if it changed.
+// The helper is a static binary without the Pulumi SDK, so Go rebuilds it from its cache unless its sources changed.
+// It returns the resource the objects depend on.
+// ctx: Pulumi context.
+// conn: SSH connection arguments.
+// serverConfig: Server configuration.
+// opts: Additional Pulumi resource options.
+func InstallHelper(
+	ctx *pulumi.Context,
+	conn *remote.ConnectionArgs,
+	serverConfig *server.Config,
+	opts ...pulumi.ResourceOption,
+) (pulumi.Resource, error) {
+	arch := serverUtil.Arch(*serverConfig.Type)
+	binary := fmt.Sprintf("./outputs/mailcow-object-%s", arch)
+
+	//nolint:gosec // the arguments are constants and the architecture is derived from the server type
+	build := exec.Command("go", "build", "-trimpath", "-buildvcs=false", "-o", binary, helperSource)
+	build.Env = append(os.Environ(), "CGO_ENABLED=0", "GOOS=linux", fmt.Sprintf("GOARCH=%s", arch))
+	if output, bErr := build.CombinedOutput(); bErr != nil {
+		return nil, fmt.Errorf("failed to build the mailcow object helper: %w: %s", bErr, output)
+	}
+	hash, hErr := file.Hash(binary)
+	if hErr != nil {
+		return nil, hErr
+	}
+
+	helperCopy, cErr := remote.NewCopyToRemote(ctx, "remote-copy-mailcow-object", &remote.CopyToRemoteArgs{
+		Source:     pulumi.NewFileAsset(binary),
+		RemotePath: pulumi.String(helperPath),
+		Triggers:   pulumi.Array{pulumi.String(*hash)},
+		Connection: conn,
+	}, opts...)
+	if cErr != nil {
+		return nil, cErr
+	}
+	return remote.NewCommand(ctx, "remote-command-mailcow-object", &remote.CommandArgs{
+		Create:     pulumi.Sprintf("chmod 755 %s", helperPath),
+		Update:     pulumi.Sprintf("chmod 755 %s", helperPath),
+		Triggers:   pulumi.Array{pulumi.String(*hash)},
+		Connection: conn,
+	}, append(opts, pulumi.DependsOn([]pulumi.Resource{helperCopy}))...)
+}
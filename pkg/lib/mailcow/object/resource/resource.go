@@ -0,0 +1,118 @@
+// Package resource manages mailcow objects as Pulumi resources, applied on the mailcow server by the object helper.
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object"
+)
+
+// Connection defines how to reach the mailcow API.
+type Connection struct {
+	// SSH are the connection arguments of the mailcow server; the objects are applied there via the local API.
+	SSH *remote.ConnectionArgs
+	// APIKey is the read-write mailcow API key.
+	APIKey pulumi.StringInput
+	// Ready are the resources the objects depend on, including the installed helper.
+	Ready pulumi.ResourceArrayInput
+}
+
+// Manage creates a Pulumi resource managing a mailcow object via the mailcow API.
+// The object is created (or adopted) with the resource, updated when its attributes change,
+// and deleted from mailcow when it is removed from the configuration.
+// ctx: Pulumi context.
+// obj: The object.
+// conn: The connection to the mailcow API.
+// password: The password of a mailbox (optional).
+// opts: Additional Pulumi resource options.
+func Manage(
+	ctx *pulumi.Context,
+	obj *object.Object,
+	conn *Connection,
+	password pulumi.StringInput,
+	opts ...pulumi.ResourceOption,
+) (*remote.Command, error) {
+	return manage(ctx, ResourceName(obj.Kind, obj.Name), pulumi.ToOutput(obj), nil, conn, password, opts...)
+}
+
+// ManageOutput creates a Pulumi resource managing a mailcow object which is only known once the output resolves.
+// The object is replaced if its name changes.
+// ctx: Pulumi context.
+// kind: The kind of the object.
+// name: The name of the resource (e.g. 'backup-mx-ipv4').
+// obj: The output of the object.
+// conn: The connection to the mailcow API.
+// opts: Additional Pulumi resource options.
+func ManageOutput(
+	ctx *pulumi.Context,
+	kind object.Kind,
+	name string,
+	obj pulumi.Output,
+	conn *Connection,
+	opts ...pulumi.ResourceOption,
+) (*remote.Command, error) {
+	identity, _ := obj.ApplyT(func(value any) string {
+		managed, _ := value.(*object.Object)
+		return managed.Name
+	}).(pulumi.StringOutput)
+	return manage(ctx, ResourceName(kind, name), obj, pulumi.Array{identity}, conn, nil, opts...)
+}
+
+// manage creates the Pulumi resource applying the lifecycle actions to a mailcow object.
+// The request is passed to the helper on stdin, so the API key and the password aren't exposed in the process list.
+// ctx: Pulumi context.
+// name: The name of the resource.
+// obj: The output of the object.
+// triggers: Values replacing the resource if they change (optional).
+// conn: The connection to the mailcow API.
+// password: The password of a mailbox (optional).
+// opts: Additional Pulumi resource options.
+func manage(
+	ctx *pulumi.Context,
+	name string,
+	obj pulumi.Output,
+	triggers pulumi.ArrayInput,
+	conn *Connection,
+	password pulumi.StringInput,
+	opts ...pulumi.ResourceOption,
+) (*remote.Command, error) {
+	if password == nil {
+		password = pulumi.String("")
+	}
+	if conn.Ready != nil {
+		opts = append(opts, pulumi.DependsOnInputs(conn.Ready))
+	}
+
+	request, _ := pulumi.All(obj, conn.APIKey, password).ApplyT(func(args []any) (string, error) {
+		managed, _ := args[0].(*object.Object)
+		apiKey, _ := args[1].(string)
+		pw, _ := args[2].(string)
+		data, mErr := json.Marshal(&object.Request{
+			APIKey:   apiKey,
+			Object:   managed,
+			Password: pw,
+		})
+		return string(data), mErr
+	}).(pulumi.StringOutput)
+
+	return remote.NewCommand(ctx, name, &remote.CommandArgs{
+		Create:     pulumi.String(fmt.Sprintf("%s %s", helperPath, object.ActionCreate)),
+		Update:     pulumi.String(fmt.Sprintf("%s %s", helperPath, object.ActionUpdate)),
+		Delete:     pulumi.String(fmt.Sprintf("%s %s", helperPath, object.ActionDelete)),
+		Stdin:      pulumi.ToSecret(request).(pulumi.StringOutput),
+		Triggers:   triggers,
+		Connection: conn.SSH,
+	}, opts...)
+}
+
+// ResourceName returns the Pulumi resource name of a mailcow object.
+// kind: The kind of the object.
+// name: The name of the object.
+func ResourceName(kind object.Kind, name string) string {
+	return fmt.Sprintf("remote-command-mailcow-%s-%s", kind, strings.NewReplacer("@", "-at-", ".", "-").Replace(name))
+}
@@ -0,0 +1,290 @@
+package mailcow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object/resource"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	mcConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mailcow"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/random"
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+)
+
+// manageObjects manages the domains, mailboxes, aliases, and alias domains declared in the configuration
+// as Pulumi resources calling the local mailcow API on the server. Existing objects are adopted,
+// changed objects are updated, and objects removed from the configuration are deleted from mailcow.
+// It returns a Pulumi Output of the mailbox passwords, which resolves once the objects are managed,
+// and the connection to the mailcow API for further objects, which depend on the managed objects.
+// ctx: Pulumi context.
+// conn: SSH connection arguments.
+// apiKey: The read-write mailcow API key.
+// mailConfig: Mail configuration.
+// serverConfig: Server configuration.
+// smarthostTask: The smarthost configuration task output to depend on.
+// opts: Additional Pulumi resource options.
+func manageObjects(ctx *pulumi.Context,
+	conn *remote.ConnectionArgs,
+	apiKey pulumi.StringOutput,
+	mailConfig *mailConf.Config,
+	serverConfig *server.Config,
+	smarthostTask pulumi.Output,
+	opts ...pulumi.ResourceOption,
+) (pulumi.MapOutput, *resource.Connection, error) {
+	objects := &mcConf.Config{}
+	if mailConfig.Mailcow != nil {
+		managed := *mailConfig.Mailcow
+		objects = &managed
+	}
+	objects.Mailboxes = mail.Mailboxes(mailConfig)
+
+	passwords, pErr := createMailboxPasswords(ctx, objects.Mailboxes)
+	if pErr != nil {
+		return pulumi.MapOutput{}, nil, pErr
+	}
+
+	helper, hErr := resource.InstallHelper(ctx, conn, serverConfig, opts...)
+	if hErr != nil {
+		return pulumi.MapOutput{}, nil, hErr
+	}
+
+	readyFn, rErr := file.ReadContents("./assets/mailcow/api-ready.sh")
+	if rErr != nil {
+		return pulumi.MapOutput{}, nil, rErr
+	}
+	ready := smarthostTask.ApplyT(func(smarthost any) []pulumi.Resource {
+		smarthoster, _ := smarthost.(pulumi.ResourceOption)
+		cmd, _ := remote.NewCommand(ctx, "remote-command-mailcow-api-ready", &remote.CommandArgs{
+			Create:     pulumi.String(readyFn),
+			Connection: conn,
+		}, append(opts, smarthoster)...)
+		return []pulumi.Resource{helper, cmd}
+	}).(pulumi.ResourceArrayOutput)
+
+	apiConn := &resource.Connection{
+		SSH:    conn,
+		APIKey: apiKey,
+		Ready:  ready,
+	}
+	managed, cErr := createObjects(ctx, apiConn, mailConfig, objects, passwords, opts...)
+	if cErr != nil {
+		return pulumi.MapOutput{}, nil, cErr
+	}
+
+	mailboxPasswords := pulumi.Map{}
+	for address, password := range passwords {
+		mailboxPasswords[address] = password
+	}
+	outputs := pulumi.Array{}
+	resources := pulumi.ResourceArray{}
+	for _, cmd := range managed {
+		outputs = append(outputs, cmd.Stdout)
+		resources = append(resources, pulumi.NewResourceInput(cmd))
+	}
+	result, _ := pulumi.All(mailboxPasswords.ToMapOutput(), outputs.ToArrayOutput()).
+		ApplyT(func(args []any) map[string]any {
+			values, _ := args[0].(map[string]any)
+			return values
+		}).(pulumi.MapOutput)
+
+	return result, &resource.Connection{
+		SSH:    conn,
+		APIKey: apiKey,
+		Ready:  resources,
+	}, nil
+}
+
+// createObjects creates the resources of the mailcow objects.
+// Mailboxes and alias domains depend on their domains, aliases on all domains and mailboxes,
+// so that objects are deleted before the objects they refer to.
+// It returns the resources of all objects.
+// ctx: Pulumi context.
+// conn: The connection to the mailcow API.
+// mailConfig: Mail configuration.
+// objects: The objects to manage.
+// passwords: The passwords of the mailboxes.
+// opts: Additional Pulumi resource options.
+func createObjects(
+	ctx *pulumi.Context,
+	conn *resource.Connection,
+	mailConfig *mailConf.Config,
+	objects *mcConf.Config,
+	passwords map[string]pulumi.StringOutput,
+	opts ...pulumi.ResourceOption,
+) ([]*remote.Command, error) {
+	created := []*remote.Command{}
+	domains := map[string]pulumi.Resource{}
+	parents := []pulumi.Resource{}
+
+	settings := map[string]*mcConf.DomainConfig{}
+	for _, domain := range objects.Domains {
+		settings[*domain.Name] = domain
+	}
+	for _, name := range mail.Domains(mailConfig) {
+		relayhost := ""
+		if smarthost := mail.SmarthostForDomain(mailConfig, name); smarthost != nil {
+			relayhost = mail.SmarthostHostname(smarthost)
+		}
+		var attributes *api.DomainAttributes
+		if settings[name] != nil {
+			attributes = domainAttributes(settings[name])
+		}
+
+		cmd, err := resource.Manage(ctx, &object.Object{
+			Kind:      object.KindDomain,
+			Name:      name,
+			Domain:    attributes,
+			Relayhost: &relayhost,
+		}, conn, nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		domains[name] = cmd
+		parents = append(parents, cmd)
+		created = append(created, cmd)
+	}
+
+	for _, domainAlias := range objects.DomainAliases {
+		cmd, err := resource.Manage(ctx, &object.Object{
+			Kind:   object.KindAliasDomain,
+			Name:   *domainAlias.Alias,
+			Target: *domainAlias.Target,
+			Active: activeFlag(domainAlias.Active),
+		}, conn, nil, append(opts, dependsOnDomain(domains, *domainAlias.Target))...)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, cmd)
+	}
+
+	for _, mailbox := range objects.Mailboxes {
+		_, domain, aErr := mail.SplitAddress(*mailbox.Address)
+		if aErr != nil {
+			return nil, aErr
+		}
+		cmd, err := resource.Manage(ctx, &object.Object{
+			Kind: object.KindMailbox,
+			Name: *mailbox.Address,
+			Mailbox: &api.MailboxAttributes{
+				Name:   defaults.GetOrDefault(mailbox.Name, ""),
+				Quota:  defaults.GetOrDefault(mailbox.Quota, 0),
+				Active: activeFlag(mailbox.Active),
+			},
+			// the probe logs in with the password from Vault, so changes in mailcow are reverted
+			EnforcePassword: mail.ProbeEnabled(mailConfig) && *mailbox.Address == mail.ProbeMailbox(mailConfig),
+		}, conn, passwords[*mailbox.Address], append(opts, dependsOnDomain(domains, domain))...)
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, cmd)
+		created = append(created, cmd)
+	}
+
+	for _, alias := range objects.Aliases {
+		cmd, err := resource.Manage(ctx, &object.Object{
+			Kind:   object.KindAlias,
+			Name:   *alias.Address,
+			Target: strings.Join(alias.Goto, ","),
+			Active: activeFlag(alias.Active),
+		}, conn, nil, append(opts, pulumi.DependsOn(parents))...)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, cmd)
+	}
+
+	return created, nil
+}
+
+// dependsOnDomain returns a resource option depending on the resource of a managed domain.
+// domains: The resources of the managed domains, by name.
+// name: The domain name.
+func dependsOnDomain(domains map[string]pulumi.Resource, name string) pulumi.ResourceOption {
+	if domain, ok := domains[name]; ok {
+		return pulumi.DependsOn([]pulumi.Resource{domain})
+	}
+	return pulumi.DependsOn(nil)
+}
+
+// createMailboxPasswords creates the initial passwords of all mailboxes and stores them in Vault.
+// It returns the passwords by mailbox address.
+// ctx: Pulumi context.
+// mailboxes: The mailboxes to create passwords for.
+func createMailboxPasswords(
+	ctx *pulumi.Context,
+	mailboxes []*mcConf.MailboxConfig,
+) (map[string]pulumi.StringOutput, error) {
+	passwords := map[string]pulumi.StringOutput{}
+	for _, mailbox := range mailboxes {
+		name := strings.NewReplacer("@", "-at-", ".", "-").Replace(*mailbox.Address)
+
+		password, pErr := random.CreatePassword(
+			ctx,
+			fmt.Sprintf("password-mailcow-mailbox-%s", name),
+			&random.PasswordOptions{
+				Special: false,
+			},
+		)
+		if pErr != nil {
+			return nil, pErr
+		}
+
+		address := *mailbox.Address
+		value, _ := password.Password.ApplyT(func(pw string) string {
+			secretValue, _ := json.Marshal(map[string]string{
+				"username": address,
+				"password": pw,
+			})
+			return string(secretValue)
+		}).(pulumi.StringOutput)
+		_, sErr := secret.Create(ctx, &secret.CreateOptions{
+			Path:  config.GlobalName,
+			Key:   fmt.Sprintf("mailcow-mailbox-%s", name),
+			Value: value,
+		})
+		if sErr != nil {
+			return nil, sErr
+		}
+
+		passwords[address] = password.Password
+	}
+	return passwords, nil
+}
+
+// domainAttributes returns the API attributes of a domain, falling back to mailcow's defaults.
+// domain: The declared domain settings.
+func domainAttributes(domain *mcConf.DomainConfig) *api.DomainAttributes {
+	attributes := object.DefaultDomainAttributes()
+
+	attributes.Description = defaults.GetOrDefault(domain.Description, "")
+	attributes.Mailboxes = defaults.GetOrDefault(domain.Mailboxes, attributes.Mailboxes)
+	attributes.Aliases = defaults.GetOrDefault(domain.Aliases, attributes.Aliases)
+	attributes.Quota = defaults.GetOrDefault(domain.Quota, attributes.Quota)
+	attributes.DefaultQuota = defaults.GetOrDefault(domain.DefaultMailboxQuota, attributes.DefaultQuota)
+	attributes.MaxQuota = defaults.GetOrDefault(domain.MaxMailboxQuota, attributes.Quota)
+	if domain.RateLimit != nil {
+		attributes.RateLimitValue = defaults.GetOrDefault(domain.RateLimit.Value, 0)
+		attributes.RateLimitFrame = defaults.GetOrDefault(domain.RateLimit.Frame, attributes.RateLimitFrame)
+	}
+	return attributes
+}
+
+// activeFlag converts an optional active setting into the mailcow API representation (defaults to active).
+// active: The active setting.
+func activeFlag(active *bool) string {
+	if defaults.GetOrDefault(active, true) {
+		return "1"
+	}
+	return "0"
+}
@@ -1,83 +1,123 @@
 package mailcow
 
 import (
+	"fmt"
+
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 )
 
 // Install Mailcow on the remote server via SSH and create necessary resources.
+// It returns a Pulumi Output representing the post-installation task.
 // ctx: Pulumi context.
 // conn: SSH connection arguments.
 // installTask: The installation task output to depend on.
+// mailConfig: Mail configuration.
+// healthcheckHash: The hash of the health check script.
 // opts: Additional Pulumi resource options.
 func postinstall(ctx *pulumi.Context,
 	conn *remote.ConnectionArgs,
 	installTask pulumi.Output,
+	mailConfig *mailConf.Config,
+	healthcheckHash string,
 	opts ...pulumi.ResourceOption,
-) {
-	bodyChecksHash, _ := file.Hash("./assets/mailcow/config/body_checks.pcre")
-	bodyChecksCopy := installTask.ApplyT(func(install any) pulumi.ResourceOrInvokeOption {
-		installer, _ := install.(pulumi.ResourceOption)
-		cmd, _ := remote.NewCopyToRemote(
-			ctx,
-			"remote-copy-mailcow-postfix-body-checks",
-			&remote.CopyToRemoteArgs{
-				Source:     pulumi.NewFileAsset("./assets/mailcow/config/body_checks.pcre"),
-				RemotePath: pulumi.String("/opt/mailcow/data/conf/postfix/body_checks.pcre"),
-				Triggers:   pulumi.Array{pulumi.String(*bodyChecksHash)},
-				Connection: conn,
-			},
-			append(opts, installer)...)
-		return pulumi.DependsOn([]pulumi.Resource{cmd})
-	})
+) (pulumi.Output, error) {
+	bodyChecksCopy, bodyChecksHash, bcErr := createCheckTable(
+		ctx,
+		conn,
+		"body_checks",
+		mail.BodyChecks(mailConfig),
+		installTask,
+		opts...,
+	)
+	if bcErr != nil {
+		return nil, bcErr
+	}
 
-	clientHeadersHash, _ := file.Hash("./assets/mailcow/config/client_headers.pcre")
-	clientHeadersCopy := installTask.ApplyT(func(install any) pulumi.ResourceOrInvokeOption {
-		installer, _ := install.(pulumi.ResourceOption)
-		cmd, _ := remote.NewCopyToRemote(
-			ctx,
-			"remote-copy-mailcow-postfix-client-headers",
-			&remote.CopyToRemoteArgs{
-				Source:     pulumi.NewFileAsset("./assets/mailcow/config/client_headers.pcre"),
-				RemotePath: pulumi.String("/opt/mailcow/data/conf/postfix/client_headers.pcre"),
-				Triggers:   pulumi.Array{pulumi.String(*clientHeadersHash)},
-				Connection: conn,
-			},
-			append(opts, installer)...)
-		return pulumi.DependsOn([]pulumi.Resource{cmd})
-	})
+	clientHeadersCopy, clientHeadersHash, chErr := createCheckTable(
+		ctx,
+		conn,
+		"client_headers",
+		mail.ClientChecks(mailConfig),
+		installTask,
+		opts...,
+	)
+	if chErr != nil {
+		return nil, chErr
+	}
 
-	postfixExtraHash, _ := file.Hash("./assets/mailcow/config/extra.cf")
-	postfixExtraCopy := installTask.ApplyT(func(install any) pulumi.ResourceOrInvokeOption {
-		installer, _ := install.(pulumi.ResourceOption)
+	postfixExtra, _ := template.Render("./assets/mailcow/config/extra.cf.j2", map[string]any{
+		"mailname":         mail.Mailname(*mailConfig.Main.Name),
+		"mynetworks":       mail.PostfixNetworks(mailConfig),
+		"messageSizeLimit": postfixMessageSizeLimit(mailConfig),
+		"parameters":       postfixParameters(mailConfig),
+	})
+	postfixExtraHash := file.WritePulumi("./outputs/mailcow_extra.cf", pulumi.String(postfixExtra)).
+		ApplyT(func(_ string) string {
+			hash, _ := file.Hash("./outputs/mailcow_extra.cf")
+			return *hash
+		}).(pulumi.StringOutput)
+	postfixExtraCopy := pulumi.All(postfixExtraHash, installTask).ApplyT(func(args []any) pulumi.ResourceOrInvokeOption {
+		hash, _ := args[0].(string)
+		installer, _ := args[1].(pulumi.ResourceOption)
 		cmd, _ := remote.NewCopyToRemote(
 			ctx,
 			"remote-copy-mailcow-postfix-extra",
 			&remote.CopyToRemoteArgs{
-				Source:     pulumi.NewFileAsset("./assets/mailcow/config/extra.cf"),
+				Source:     pulumi.NewFileAsset("./outputs/mailcow_extra.cf"),
 				RemotePath: pulumi.String("/opt/mailcow/data/conf/postfix/extra.cf"),
-				Triggers:   pulumi.Array{pulumi.String(*postfixExtraHash)},
+				Triggers:   pulumi.Array{pulumi.String(hash)},
 				Connection: conn,
 			},
 			append(opts, installer)...)
 		return pulumi.DependsOn([]pulumi.Resource{cmd})
 	})
 
-	pulumi.All(postfixExtraCopy, bodyChecksCopy, clientHeadersCopy, installTask).
-		ApplyT(func(args []any) error {
+	postinstallTask := pulumi.All(postfixExtraCopy, bodyChecksCopy, clientHeadersCopy, installTask).
+		ApplyT(func(args []any) pulumi.ResourceOption {
 			postfixExtra, _ := args[0].(pulumi.ResourceOption)
 			bodyChecks, _ := args[1].(pulumi.ResourceOption)
 			clientHeaders, _ := args[2].(pulumi.ResourceOption)
 			installer, _ := args[3].(pulumi.ResourceOption)
 
-			install.Postinstall(ctx, "mailcow", pulumi.Array{
-				pulumi.String(*postfixExtraHash),
-				pulumi.String(*bodyChecksHash),
-				pulumi.String(*clientHeadersHash),
-			}, conn, append(opts, postfixExtra, bodyChecks, clientHeaders, installer)...)
-			return nil
+			triggers := pulumi.Array{
+				postfixExtraHash,
+				bodyChecksHash,
+				clientHeadersHash,
+			}
+			postinstallOpts := install.Postinstall(ctx, "mailcow", triggers, conn,
+				append(opts, postfixExtra, bodyChecks, clientHeaders, installer)...)
+
+			mailname := mail.Mailname(*mailConfig.Main.Name)
+			healthcheckOpts := install.Healthcheck(ctx, "mailcow", fmt.Sprintf("https://%s/", mailname), mailname,
+				append(triggers, pulumi.String(healthcheckHash)), conn, postinstallOpts...)
+			// the last option is the dependency on the health check command
+			return healthcheckOpts[len(healthcheckOpts)-1]
 		})
+
+	return postinstallTask, nil
+}
+
+// postfixMessageSizeLimit returns the configured Postfix message size limit, or 0 if not set.
+// mailConfig: Mail configuration.
+func postfixMessageSizeLimit(mailConfig *mailConf.Config) int {
+	if mailConfig.Postfix == nil || mailConfig.Postfix.MessageSizeLimit == nil {
+		return 0
+	}
+	return *mailConfig.Postfix.MessageSizeLimit
+}
+
+// postfixParameters returns the configured additional Postfix parameters.
+// mailConfig: Mail configuration.
+func postfixParameters(mailConfig *mailConf.Config) map[string]string {
+	if mailConfig.Postfix == nil {
+		return nil
+	}
+	return mailConfig.Postfix.Parameters
 }
@@ -7,6 +7,7 @@ import (
 	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/google/dns/record"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
@@ -19,6 +20,9 @@ const recordA = "A"
 // recordAAAA is a constant representing the AAAA DNS record type.
 const recordAAAA = "AAAA"
 
+// recordTXT is a constant representing the TXT DNS record type.
+const recordTXT = "TXT"
+
 // CreateDNSRecords creates DNS records for Mailcow based on the provided DNS configuration.
 // ctx: The Pulumi context for resource creation.
 // dnsConfig: The DNS configuration containing domain and record details.
@@ -57,14 +61,14 @@ func CreateDNSRecords(
 	}
 
 	// main domain
-	dnsErr := createDomainRecords(ctx, mailConfig.Main, &mainServerDomain, true)
+	dnsErr := createDomainRecords(ctx, mailConfig, mailConfig.Main, &mainServerDomain, true)
 	if dnsErr != nil {
 		return dnsErr
 	}
 
 	// additional domains have a CNAME pointing to the main domain
 	for _, domain := range mailConfig.Additional {
-		drErr := createDomainRecords(ctx, domain, &mainServerDomain, false)
+		drErr := createDomainRecords(ctx, mailConfig, domain, &mainServerDomain, false)
 		if drErr != nil {
 			return drErr
 		}
@@ -75,17 +79,33 @@ func CreateDNSRecords(
 
 // createDomainRecords creates necessary DNS records for a given mail domain.
 // ctx: The Pulumi context for resource creation.
+// mailConfig: The mail configuration.
 // domain: The mail domain configuration.
 // primaryDomain: The primary domain to point records to.
 // main: A boolean indicating if this is the main domain.
 func createDomainRecords(
 	ctx *pulumi.Context,
+	mailConfig *mailConf.Config,
 	domain *dns.DomainConfig,
 	primaryDomain *pulumi.StringOutput,
 	main bool,
 ) error {
 	records := pulumi.StringArray([]pulumi.StringInput{*primaryDomain})
 
+	// the SPF record is only managed if enabled
+	if mailConfig.SPF != nil && defaults.GetOrDefault(mailConfig.SPF.Enabled, false) {
+		_, spfErr := record.Create(ctx, &record.CreateOptions{
+			Domain:     *domain.Name,
+			ZoneID:     pulumi.String(*domain.ZoneID),
+			RecordType: recordTXT,
+			Records:    pulumi.StringArray{pulumi.String(mail.SPFRecord(mailConfig, *domain.Name))},
+			Project:    domain.Project,
+		})
+		if spfErr != nil {
+			return spfErr
+		}
+	}
+
 	// if this is not the main domain, create the 'mail' record
 	if !main {
 		_, mErr := record.Create(ctx, &record.CreateOptions{
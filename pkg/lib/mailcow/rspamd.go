@@ -0,0 +1,244 @@
+package mailcow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+)
+
+// rspamdLocalFiles are the managed files merged into mailcow's rspamd 'local.d' configuration by an include,
+// keeping mailcow's defaults of the same module.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var rspamdLocalFiles = []string{"dkim_signing.conf", "multimap.conf", "greylist.conf"}
+
+// rspamdFile is a rendered rspamd configuration file.
+type rspamdFile struct {
+	// path is the path relative to the rspamd configuration directory.
+	path string
+	// content is the rendered content.
+	content pulumi.StringInput
+}
+
+// configureRspamd renders the rspamd overrides, uploads them to the remote server, and reloads rspamd.
+// The configuration is validated before the reload and rolled back if it is invalid.
+// It returns a Pulumi Output representing the reload task.
+// ctx: Pulumi context.
+// conn: SSH connection arguments.
+// mailConfig: Mail configuration.
+// arcKeys: The ARC private keys by domain.
+// installTask: The installation task output to depend on.
+// opts: Additional Pulumi resource options.
+func configureRspamd(ctx *pulumi.Context,
+	conn *remote.ConnectionArgs,
+	mailConfig *mailConf.Config,
+	arcKeys map[string]pulumi.StringOutput,
+	installTask pulumi.Output,
+	opts ...pulumi.ResourceOption,
+) (pulumi.Output, error) {
+	files, rErr := renderRspamdFiles(mailConfig, arcKeys)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	paths := []string{}
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	reloadFn, reErr := template.Render("./assets/mailcow/rspamd-reload.sh.j2", map[string]any{
+		"files":    paths,
+		"includes": rspamdLocalFiles,
+	})
+	if reErr != nil {
+		return nil, reErr
+	}
+
+	staging := installTask.ApplyT(func(install any) pulumi.ResourceOption {
+		installer, _ := install.(pulumi.ResourceOption)
+		cmd, _ := remote.NewCommand(ctx, "remote-command-mailcow-rspamd-staging", &remote.CommandArgs{
+			Create: pulumi.String(
+				"mkdir -p /opt/mailcow/data/conf/rspamd/.staging/override.d /opt/mailcow/data/conf/rspamd/.staging/custom/arc " +
+					"/opt/mailcow/data/conf/rspamd/.staging/custom/managed",
+			),
+			Connection: conn,
+		}, append(opts, installer)...)
+		return pulumi.DependsOn([]pulumi.Resource{cmd})
+	})
+
+	triggers := pulumi.Array{}
+	copies := pulumi.ResourceArray{}
+	for _, f := range files {
+		outputFile := fmt.Sprintf("./outputs/mailcow_rspamd_%s", strings.NewReplacer("/", "_", ".", "_").Replace(f.path))
+		remotePath := fmt.Sprintf("/opt/mailcow/data/conf/rspamd/.staging/%s", f.path)
+
+		fileHash := file.WritePulumi(outputFile, f.content).
+			ApplyT(func(_ string) string {
+				hash, _ := file.Hash(outputFile)
+				return *hash
+			}).(pulumi.StringOutput)
+		fileCopy := pulumi.All(fileHash, staging).ApplyT(func(args []any) pulumi.Resource {
+			hash, _ := args[0].(string)
+			stager, _ := args[1].(pulumi.ResourceOption)
+			cmd, _ := remote.NewCopyToRemote(
+				ctx,
+				fmt.Sprintf("remote-copy-mailcow-rspamd-%s", strings.NewReplacer("/", "-", ".", "-", "_", "-").Replace(f.path)),
+				&remote.CopyToRemoteArgs{
+					Source:     pulumi.NewFileAsset(outputFile),
+					RemotePath: pulumi.String(remotePath),
+					Triggers:   pulumi.Array{pulumi.String(hash)},
+					Connection: conn,
+				},
+				append(opts, stager)...)
+			return cmd
+		}).(pulumi.ResourceOutput)
+		triggers = append(triggers, fileHash)
+		copies = append(copies, fileCopy)
+	}
+
+	// the reload depends on every copied file, not only on the installation
+	reload := installTask.ApplyT(func(install any) pulumi.ResourceOption {
+		installer, _ := install.(pulumi.ResourceOption)
+		cmd, _ := remote.NewCommand(ctx, "remote-command-mailcow-rspamd-reload", &remote.CommandArgs{
+			Create:     pulumi.String(reloadFn),
+			Update:     pulumi.String(reloadFn),
+			Triggers:   triggers,
+			Connection: conn,
+		}, append(opts, installer, pulumi.DependsOnInputs(copies))...)
+		return pulumi.DependsOn([]pulumi.Resource{cmd})
+	})
+
+	return reload, nil
+}
+
+// renderRspamdFiles renders all rspamd override files from the mail configuration.
+// mailConfig: Mail configuration.
+// arcKeys: The ARC private keys by domain.
+func renderRspamdFiles(mailConfig *mailConf.Config, arcKeys map[string]pulumi.StringOutput) ([]rspamdFile, error) {
+	rspamdConfig := mailConfig.Rspamd
+	if rspamdConfig == nil {
+		rspamdConfig = &mailConf.RspamdConfig{}
+	}
+
+	files := []rspamdFile{}
+	render := func(path string, tmpl string, values map[string]any) error {
+		content, err := template.Render(fmt.Sprintf("./assets/mailcow/config/rspamd/%s", tmpl), values)
+		if err != nil {
+			return err
+		}
+		files = append(files, rspamdFile{path: path, content: pulumi.String(content)})
+		return nil
+	}
+
+	if err := render("custom/managed/dkim_signing.conf", "dkim_signing.conf.j2", map[string]any{
+		"signHeaders": strings.Join(mailConfig.DkimSignHeaders, ":"),
+	}); err != nil {
+		return nil, err
+	}
+
+	actions := map[string]any{}
+	if rspamdConfig.Actions != nil {
+		actions = map[string]any{
+			"reject":         defaults.GetOrDefault(rspamdConfig.Actions.Reject, 0),
+			"addHeader":      defaults.GetOrDefault(rspamdConfig.Actions.AddHeader, 0),
+			"rewriteSubject": defaults.GetOrDefault(rspamdConfig.Actions.RewriteSubject, 0),
+			"greylist":       defaults.GetOrDefault(rspamdConfig.Actions.Greylist, 0),
+		}
+	}
+	if err := render("override.d/actions.conf", "actions.conf.j2", actions); err != nil {
+		return nil, err
+	}
+
+	lists := []map[string]any{}
+	for _, list := range rspamdConfig.Multimap {
+		mapFile := fmt.Sprintf("custom_%s.map", strings.ToLower(*list.Name))
+		if err := render(fmt.Sprintf("custom/%s", mapFile), "map.j2", map[string]any{
+			"entries": list.Entries,
+		}); err != nil {
+			return nil, err
+		}
+		lists = append(lists, map[string]any{
+			"symbol":      mail.RspamdSymbol(*list.Name),
+			"type":        *list.Type,
+			"header":      defaults.GetOrDefault(list.Header, ""),
+			"filter":      defaults.GetOrDefault(list.Filter, ""),
+			"map":         mapFile,
+			"description": defaults.GetOrDefault(list.Description, ""),
+			"action":      defaults.GetOrDefault(list.Action, ""),
+			"score":       defaults.GetOrDefault(list.Score, 0),
+		})
+	}
+	if err := render("custom/managed/multimap.conf", "multimap.conf.j2", map[string]any{
+		"lists": lists,
+	}); err != nil {
+		return nil, err
+	}
+
+	greylisting := map[string]any{"configured": false}
+	if rspamdConfig.Greylisting != nil {
+		greylisting = map[string]any{
+			"configured": true,
+			"enabled":    defaults.GetOrDefault(rspamdConfig.Greylisting.Enabled, true),
+			"timeout":    defaults.GetOrDefault(rspamdConfig.Greylisting.Timeout, ""),
+			"expire":     defaults.GetOrDefault(rspamdConfig.Greylisting.Expire, ""),
+		}
+	}
+	if err := render("custom/managed/greylist.conf", "greylist.conf.j2", greylisting); err != nil {
+		return nil, err
+	}
+
+	limits := []map[string]any{}
+	for _, limit := range rspamdConfig.RateLimits {
+		limits = append(limits, map[string]any{
+			"name":     strings.ToLower(*limit.Name),
+			"selector": *limit.Selector,
+			"burst":    *limit.Burst,
+			"rate":     *limit.Rate,
+		})
+	}
+	if err := render("override.d/ratelimit.conf", "ratelimit.conf.j2", map[string]any{
+		"limits": limits,
+	}); err != nil {
+		return nil, err
+	}
+
+	arcFiles, aErr := renderARCFiles(mailConfig, arcKeys)
+	if aErr != nil {
+		return nil, aErr
+	}
+
+	return append(files, arcFiles...), nil
+}
+
+// renderARCFiles renders the ARC signing configuration and the private keys of all signing domains.
+// mailConfig: Mail configuration.
+// arcKeys: The ARC private keys by domain.
+func renderARCFiles(mailConfig *mailConf.Config, arcKeys map[string]pulumi.StringOutput) ([]rspamdFile, error) {
+	files := []rspamdFile{}
+	domains := []string{}
+	for _, domain := range mail.Domains(mailConfig) {
+		key, ok := arcKeys[domain]
+		if !ok {
+			continue
+		}
+		files = append(files, rspamdFile{path: fmt.Sprintf("custom/arc/%s.key", domain), content: key})
+		domains = append(domains, domain)
+	}
+
+	content, err := template.Render("./assets/mailcow/config/rspamd/arc.conf.j2", map[string]any{
+		"domains":  domains,
+		"selector": mail.ARCSelector(mailConfig),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, rspamdFile{path: "override.d/arc.conf", content: pulumi.String(content)}), nil
+}
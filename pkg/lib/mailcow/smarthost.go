@@ -0,0 +1,118 @@
+package mailcow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/shell"
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+)
+
+// configureSmarthosts stores the smarthost credentials in Vault and creates or updates the smarthosts
+// as relayhosts via the mailcow API; the domain objects assign them as sender-dependent transports.
+// It returns a Pulumi Output representing the smarthost configuration task.
+// ctx: Pulumi context.
+// conn: SSH connection arguments.
+// apiKey: The read-write mailcow API key.
+// mailConfig: Mail configuration.
+// postinstallTask: The post-installation task output to depend on.
+// opts: Additional Pulumi resource options.
+func configureSmarthosts(ctx *pulumi.Context,
+	conn *remote.ConnectionArgs,
+	apiKey pulumi.StringOutput,
+	mailConfig *mailConf.Config,
+	postinstallTask pulumi.Output,
+	opts ...pulumi.ResourceOption,
+) (pulumi.Output, error) {
+	smarthosts := []map[string]any{}
+	passwords := pulumi.Array{}
+	for _, smarthost := range mailConfig.Smarthosts {
+		hostname := mail.SmarthostHostname(smarthost)
+		username := defaults.GetOrDefault(smarthost.Username, "")
+
+		password := pulumi.ToSecret(pulumi.String("")).(pulumi.StringOutput)
+		if smarthost.PasswordSecret != nil {
+			password = config.RequireSecret(ctx, *smarthost.PasswordSecret)
+		}
+		credentials, _ := password.ApplyT(func(pw string) string {
+			value, _ := json.Marshal(map[string]string{
+				"hostname": hostname,
+				"username": username,
+				"password": pw,
+			})
+			return string(value)
+		}).(pulumi.StringOutput)
+		_, sErr := secret.Create(ctx, &secret.CreateOptions{
+			Path:  config.GlobalName,
+			Key:   fmt.Sprintf("mailcow-smarthost-%s", strings.ReplaceAll(*smarthost.Host, ".", "-")),
+			Value: credentials,
+		})
+		if sErr != nil {
+			return nil, sErr
+		}
+
+		smarthosts = append(smarthosts, map[string]any{
+			"hostname": shell.Quote(hostname),
+			"username": shell.Quote(username),
+		})
+		passwords = append(passwords, password)
+	}
+
+	script, _ := pulumi.All(apiKey, passwords).ApplyT(func(args []any) string {
+		key, _ := args[0].(string)
+		values, _ := args[1].([]any)
+		for i, value := range values {
+			pw, _ := value.(string)
+			smarthosts[i]["password"] = shell.Quote(pw)
+		}
+		sc, _ := template.Render("./assets/mailcow/smarthost.sh.j2", map[string]any{
+			"apiKey":     key,
+			"smarthosts": smarthosts,
+		})
+		return sc
+	}).(pulumi.StringOutput)
+	scriptHash := file.WritePulumi("./outputs/mailcow_smarthost.sh", script).
+		ApplyT(func(_ string) string {
+			hash, _ := file.Hash("./outputs/mailcow_smarthost.sh")
+			return *hash
+		})
+
+	smarthostTask := pulumi.All(scriptHash, postinstallTask).ApplyT(func(args []any) pulumi.ResourceOption {
+		hash, _ := args[0].(string)
+		postinstaller, _ := args[1].(pulumi.ResourceOption)
+
+		scriptCopy, _ := remote.NewCopyToRemote(
+			ctx,
+			"remote-copy-mailcow-smarthost-sh",
+			&remote.CopyToRemoteArgs{
+				Source:     pulumi.NewFileAsset("./outputs/mailcow_smarthost.sh"),
+				RemotePath: pulumi.String("/opt/mailcow/smarthost.sh"),
+				Triggers:   pulumi.Array{pulumi.String(hash)},
+				Connection: conn,
+			},
+			append(opts, postinstaller)...)
+		cmd, _ := remote.NewCommand(
+			ctx,
+			"remote-command-mailcow-smarthosts",
+			&remote.CommandArgs{
+				Create:     pulumi.String("sh /opt/mailcow/smarthost.sh"),
+				Update:     pulumi.String("sh /opt/mailcow/smarthost.sh"),
+				Triggers:   pulumi.Array{pulumi.String(hash)},
+				Connection: conn,
+			},
+			append(opts, postinstaller, pulumi.DependsOn([]pulumi.Resource{scriptCopy}))...)
+		return pulumi.DependsOn([]pulumi.Resource{cmd})
+	})
+
+	return smarthostTask, nil
+}
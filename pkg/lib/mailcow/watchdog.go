@@ -0,0 +1,132 @@
+package mailcow
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/ntfy"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	ntfyConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/ntfy"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/shell"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+)
+
+// watchdogNtfyUser is the name of the Ntfy user notified by the watchdog.
+const watchdogNtfyUser = "mailcow-watchdog"
+
+// CreateWatchdogNtfyUser creates the Ntfy user the mailcow watchdog notifies, and stores its credentials in Vault.
+// It returns nil if the watchdog doesn't notify Ntfy.
+// ctx: Pulumi context.
+// mailConfig: Mail configuration.
+func CreateWatchdogNtfyUser(ctx *pulumi.Context, mailConfig *mailConf.Config) (*ntfyModel.User, error) {
+	if !mail.WatchdogNtfyEnabled(mailConfig) {
+		return nil, nil
+	}
+	return ntfy.CreateUser(ctx, watchdogNtfyUser, mail.WatchdogNtfyTopic(mailConfig), "mailcow-watchdog-ntfy")
+}
+
+// watchdogWebhook returns the webhook URL and body publishing the watchdog notifications to the Ntfy topic.
+// The access token is passed as 'auth' query parameter because the watchdog can't send custom headers.
+// It returns empty values if the watchdog doesn't notify Ntfy.
+// ntfyConfig: Ntfy configuration.
+// watchdogUser: The Ntfy user of the watchdog (optional).
+func watchdogWebhook(ntfyConfig *ntfyConf.Config, watchdogUser *ntfyModel.User) (pulumi.StringOutput, string) {
+	if watchdogUser == nil {
+		return pulumi.String("").ToStringOutput(), ""
+	}
+
+	url, _ := watchdogUser.Token.ApplyT(func(token string) string {
+		auth := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("Bearer %s", token)))
+		return fmt.Sprintf("https://%s/?auth=%s", *ntfyConfig.Domain.Name, auth)
+	}).(pulumi.StringOutput)
+	body := fmt.Sprintf(`{"topic": "%s", "title": "${SUBJECT}", "message": "${BODY}"}`, watchdogUser.Topic)
+	return url, body
+}
+
+// testWatchdogNotifications sends a test notification to all watchdog notification targets and confirms the delivery.
+// The Ntfy notification is confirmed by reading it back from the topic,
+// and the e-mail notifications by their successful delivery in the Postfix log.
+// ctx: Pulumi context.
+// conn: SSH connection arguments.
+// mailConfig: Mail configuration.
+// ntfyConfig: Ntfy configuration.
+// watchdogUser: The Ntfy user of the watchdog (optional).
+// postinstallTask: The post-installation task output to depend on.
+// ntfyTask: The Ntfy installation task output provisioning the watchdog user.
+// opts: Additional Pulumi resource options.
+func testWatchdogNotifications(ctx *pulumi.Context,
+	conn *remote.ConnectionArgs,
+	mailConfig *mailConf.Config,
+	ntfyConfig *ntfyConf.Config,
+	watchdogUser *ntfyModel.User,
+	postinstallTask pulumi.Output,
+	ntfyTask pulumi.Output,
+	opts ...pulumi.ResourceOption,
+) {
+	emails := []string{}
+	for _, address := range mail.WatchdogNotifyEmail(mailConfig) {
+		emails = append(emails, shell.Quote(address))
+	}
+	if watchdogUser == nil && len(emails) == 0 {
+		return
+	}
+
+	token := pulumi.String("").ToStringOutput()
+	if watchdogUser != nil {
+		token = watchdogUser.Token
+	}
+	script, _ := token.ApplyT(func(tk string) string {
+		values := map[string]any{
+			"mailname": mail.Mailname(*mailConfig.Main.Name),
+			"emails":   emails,
+		}
+		if watchdogUser != nil {
+			values["ntfy"] = map[string]string{
+				"url":   fmt.Sprintf("https://%s", *ntfyConfig.Domain.Name),
+				"topic": watchdogUser.Topic,
+				"token": tk,
+			}
+		}
+		sc, _ := template.Render("./assets/mailcow/watchdog-test.sh.j2", values)
+		return sc
+	}).(pulumi.StringOutput)
+	scriptHash := file.WritePulumi("./outputs/mailcow_watchdog-test.sh", script).
+		ApplyT(func(_ string) string {
+			hash, _ := file.Hash("./outputs/mailcow_watchdog-test.sh")
+			return *hash
+		})
+
+	pulumi.All(scriptHash, postinstallTask, ntfyTask).ApplyT(func(args []any) error {
+		hash, _ := args[0].(string)
+		postinstaller, _ := args[1].(pulumi.ResourceOption)
+		ntfyInstaller, _ := args[2].(pulumi.ResourceOption)
+
+		scriptCopy, _ := remote.NewCopyToRemote(
+			ctx,
+			"remote-copy-mailcow-watchdog-test-sh",
+			&remote.CopyToRemoteArgs{
+				Source:     pulumi.NewFileAsset("./outputs/mailcow_watchdog-test.sh"),
+				RemotePath: pulumi.String("/opt/mailcow/watchdog-test.sh"),
+				Triggers:   pulumi.Array{pulumi.String(hash)},
+				Connection: conn,
+			},
+			append(opts, postinstaller, ntfyInstaller)...)
+		_, _ = remote.NewCommand(
+			ctx,
+			"remote-command-mailcow-watchdog-test",
+			&remote.CommandArgs{
+				Create:     pulumi.String("sh /opt/mailcow/watchdog-test.sh"),
+				Update:     pulumi.String("sh /opt/mailcow/watchdog-test.sh"),
+				Triggers:   pulumi.Array{pulumi.String(hash)},
+				Connection: conn,
+			},
+			append(opts, postinstaller, ntfyInstaller, pulumi.DependsOn([]pulumi.Resource{scriptCopy}))...)
+		return nil
+	})
+}
@@ -0,0 +1,139 @@
+package mirror
+
+import (
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	fileUtil "github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	scalewayConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/scaleway"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/file"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+)
+
+// Install the registry mirror, a pull-through cache of Docker Hub backed by object storage, on the remote server via SSH.
+// ctx: Pulumi context.
+// sshIPv4: The IPv4 address of the server to connect to via SSH.
+// privateKeyPem: The private key in PEM format to use for SSH authentication.
+// serverConfig: Server configuration.
+// scalewayConfig: Configuration for Scaleway.
+// dependsOn: Pulumi resource option to specify dependencies.
+func Install(
+	ctx *pulumi.Context,
+	sshIPv4 pulumi.StringOutput,
+	privateKeyPem pulumi.StringOutput,
+	serverConfig *server.Config,
+	scalewayConfig *scalewayConf.Config,
+	dependsOn pulumi.ResourceOrInvokeOption,
+) (*remote.Command, pulumi.MapOutput, error) {
+	conn := &remote.ConnectionArgs{
+		Host:       sshIPv4,
+		PrivateKey: privateKeyPem,
+		User:       pulumi.String("root"),
+	}
+
+	opts := []pulumi.ResourceOption{dependsOn}
+
+	opts, prepErr := install.Prepare(ctx, "mirror", conn, opts...)
+	if prepErr != nil {
+		return nil, pulumi.MapOutput{}, prepErr
+	}
+
+	dockerCompose, dcErr := template.Render("./assets/mirror/docker-compose.yml.j2", map[string]any{})
+	if dcErr != nil {
+		return nil, pulumi.MapOutput{}, dcErr
+	}
+	dockerComposeCopy, dockerComposeHash, dcErr := install.DockerCompose(
+		ctx,
+		"mirror",
+		pulumi.String(dockerCompose),
+		false,
+		serverConfig,
+		conn,
+		opts...)
+	if dcErr != nil {
+		return nil, pulumi.MapOutput{}, dcErr
+	}
+
+	opts, verifyImagesHash, viErr := install.VerifyImages(
+		ctx,
+		"mirror",
+		serverConfig,
+		conn,
+		opts...)
+	if viErr != nil {
+		return nil, pulumi.MapOutput{}, viErr
+	}
+
+	storage, stErr := createStorage(ctx, serverConfig, scalewayConfig)
+	if stErr != nil {
+		return nil, pulumi.MapOutput{}, stErr
+	}
+	password := pulumi.String("").ToStringOutput()
+	if serverConfig.RegistryMirror.PasswordSecret != nil {
+		password = config.RequireSecret(ctx, *serverConfig.RegistryMirror.PasswordSecret)
+	}
+	configFile, _ := pulumi.All(storage, password).ApplyT(func(args []any) string {
+		storageValues, _ := args[0].(map[string]any)
+		pw, _ := args[1].(string)
+		cfg, _ := template.Render("./assets/mirror/config.yml.j2", map[string]any{
+			"storage":  storageValues,
+			"ttl":      serverUtil.MirrorTTL(serverConfig),
+			"username": defaults.GetOrDefault(serverConfig.RegistryMirror.Username, ""),
+			"password": pw,
+		})
+		return cfg
+	}).(pulumi.StringOutput)
+	configFileHash, _ := file.WriteAndUpload(ctx, "mirror_config.yml", configFile).
+		ApplyT(func(_ any) string {
+			hash, _ := fileUtil.Hash("./outputs/mirror_config.yml")
+			return *hash
+		}).(pulumi.StringOutput)
+	configFileCopy := configFileHash.ApplyT(func(_ string) pulumi.ResourceOption {
+		cmd, _ := remote.NewCopyToRemote(
+			ctx,
+			"remote-copy-mirror-config",
+			&remote.CopyToRemoteArgs{
+				Source:     pulumi.NewFileAsset("./outputs/mirror_config.yml"),
+				RemotePath: pulumi.String("/opt/mirror/config.yml"),
+				Triggers:   pulumi.Array{configFileHash},
+				Connection: conn,
+			},
+			opts...)
+		return pulumi.DependsOn([]pulumi.Resource{cmd})
+	})
+
+	opts, systemdServiceHash, shErr := install.SystemDService(ctx, "mirror", conn, opts...)
+	if shErr != nil {
+		return nil, pulumi.MapOutput{}, shErr
+	}
+
+	installFn, iErr := fileUtil.ReadContents("./assets/mirror/install.sh")
+	if iErr != nil {
+		return nil, pulumi.MapOutput{}, iErr
+	}
+	installTriggers := pulumi.Array{
+		dockerComposeHash,
+		configFileHash,
+		pulumi.String(*systemdServiceHash),
+		verifyImagesHash,
+	}
+	cmd, cmdErr := remote.NewCommand(ctx, "remote-command-install-mirror", &remote.CommandArgs{
+		Create:     pulumi.StringPtr(installFn),
+		Update:     pulumi.StringPtr(installFn),
+		Triggers:   installTriggers,
+		Connection: conn,
+	}, append(opts, install.CollectResourceOptions([]pulumi.Output{
+		pulumi.ToOutput(dockerComposeCopy),
+		configFileCopy,
+	})...)...)
+	if cmdErr != nil {
+		return nil, pulumi.MapOutput{}, cmdErr
+	}
+
+	return cmd, install.Images("./outputs/mirror_docker-compose.yml", dockerComposeHash), nil
+}
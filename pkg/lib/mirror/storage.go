@@ -0,0 +1,94 @@
+package mirror
+
+import (
+	"fmt"
+
+	slApplication "github.com/muhlba91/pulumi-shared-library/pkg/util/scaleway/iam/application"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumiverse/pulumi-scaleway/sdk/go/scaleway/object"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	scalewayConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/scaleway"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	scalewayUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/scaleway"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+)
+
+// bucketActions are the actions of the registry mirror on the cached images.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var bucketActions = []string{
+	"s3:ListBucket",
+	"s3:GetObject",
+	"s3:PutObject",
+	"s3:DeleteObject",
+	"s3:ListBucketMultipartUploads",
+	"s3:ListMultipartUploadParts",
+	"s3:AbortMultipartUpload",
+}
+
+// createStorage creates the Scaleway Object Storage bucket of the cached images and an application scoped to it.
+// The application has no IAM policy; its access is granted by the bucket policy only.
+// The output resolves to the storage values of the registry configuration.
+// ctx: Pulumi context.
+// serverConfig: Server configuration.
+// scalewayConfig: Configuration for Scaleway.
+func createStorage(ctx *pulumi.Context,
+	serverConfig *server.Config,
+	scalewayConfig *scalewayConf.Config,
+) (pulumi.Output, error) {
+	resourceName := fmt.Sprintf("%s-%s-registry-mirror", config.GlobalName, config.Environment)
+	bucketRegion := serverUtil.MirrorRegion(serverConfig, config.ScalewayDefaultRegion)
+
+	app, aErr := slApplication.CreateApplication(ctx, &slApplication.CreateOptions{
+		Name:             resourceName,
+		DefaultProjectID: pulumi.StringPtrFromPtr(scalewayConfig.Project),
+	})
+	if aErr != nil {
+		return nil, aErr
+	}
+
+	scwBucket, bErr := object.NewBucket(ctx, "scw-object-bucket-registry-mirror", &object.BucketArgs{
+		Name:      pulumi.String(resourceName),
+		ProjectId: pulumi.StringPtrFromPtr(scalewayConfig.Project),
+		Region:    pulumi.String(bucketRegion),
+		Tags:      pulumi.ToStringMap(config.CommonLabels()),
+	})
+	if bErr != nil {
+		return nil, bErr
+	}
+
+	policy := pulumi.All(scwBucket.Name, app.Application.ID()).ApplyT(func(args []any) (string, error) {
+		bucketName, _ := args[0].(string)
+		applicationID, _ := args[1].(pulumi.ID)
+		return scalewayUtil.BucketPolicy(
+			"registry-mirror",
+			bucketName,
+			fmt.Sprintf("application_id:%s", applicationID),
+			bucketActions,
+			serverConfig.RegistryMirror.OwnerPrincipals,
+		)
+	}).(pulumi.StringOutput)
+	_, pErr := object.NewBucketPolicy(ctx, "scw-object-bucket-policy-registry-mirror", &object.BucketPolicyArgs{
+		Bucket:    scwBucket.Name,
+		Policy:    policy,
+		ProjectId: pulumi.StringPtrFromPtr(scalewayConfig.Project),
+		Region:    pulumi.String(bucketRegion),
+	})
+	if pErr != nil {
+		return nil, pErr
+	}
+
+	return pulumi.All(scwBucket.Name, app.Key.AccessKey, app.Key.SecretKey).ApplyT(func(args []any) map[string]any {
+		bucketName, _ := args[0].(string)
+		accessKeyID, _ := args[1].(string)
+		secretAccessKey, _ := args[2].(string)
+		return map[string]any{
+			"bucket":          bucketName,
+			"region":          bucketRegion,
+			"endpoint":        fmt.Sprintf("https://s3.%s.scw.cloud", bucketRegion),
+			"accessKeyId":     accessKeyID,
+			"secretAccessKey": secretAccessKey,
+		}
+	}), nil
+}
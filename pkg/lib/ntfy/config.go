@@ -1,10 +1,13 @@
 package ntfy
 
 import (
+	"fmt"
+
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	ntfyConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/ntfy"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/file"
 	fileUtil "github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
@@ -14,16 +17,39 @@ import (
 // ctx: Pulumi context.
 // conn: The remote connection arguments.
 // ntfyConfig: Ntfy configuration.
+// users: The provisioned Ntfy users.
 // opts: Additional Pulumi resource options.
 func createConfig(ctx *pulumi.Context,
 	conn *remote.ConnectionArgs,
 	ntfyConfig *ntfyConf.Config,
+	users []*ntfyModel.User,
 	opts ...pulumi.ResourceOption,
 ) (pulumi.Output, pulumi.StringOutput) {
-	configFile, _ := template.Render("./assets/ntfy/server.yml.j2", map[string]any{
-		"domain": ntfyConfig.Domain.Name,
-	})
-	configFileHash, _ := file.WriteAndUpload(ctx, "ntfy_server.yml", pulumi.String(configFile)).
+	credentials := []any{}
+	for _, user := range users {
+		credentials = append(credentials, user.PasswordHash, user.Token)
+	}
+	configFile, _ := pulumi.All(credentials...).ApplyT(func(args []any) string {
+		authUsers := []string{}
+		authAccess := []string{}
+		authTokens := []string{}
+		for i, user := range users {
+			passwordHash, _ := args[2*i].(string)
+			token, _ := args[2*i+1].(string)
+			authUsers = append(authUsers, fmt.Sprintf("%s:%s:user", user.Name, passwordHash))
+			authAccess = append(authAccess, fmt.Sprintf("%s:%s:rw", user.Name, user.Topic))
+			authTokens = append(authTokens, fmt.Sprintf("%s:%s", user.Name, token))
+		}
+
+		cf, _ := template.Render("./assets/ntfy/server.yml.j2", map[string]any{
+			"domain":     ntfyConfig.Domain.Name,
+			"authUsers":  authUsers,
+			"authAccess": authAccess,
+			"authTokens": authTokens,
+		})
+		return cf
+	}).(pulumi.StringOutput)
+	configFileHash, _ := file.WriteAndUpload(ctx, "ntfy_server.yml", configFile).
 		ApplyT(func(_ any) string {
 			hash, _ := fileUtil.Hash("./outputs/ntfy_server.yml")
 			return *hash
@@ -1,6 +1,8 @@
 package ntfy
 
 import (
+	"fmt"
+
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
@@ -8,17 +10,22 @@ import (
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
 	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
 	ntfyConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/ntfy"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 )
 
 // Install Ntfy on the remote server via SSH and create necessary resources.
+// It returns the task completing once Ntfy is healthy with its users provisioned, and the image inventory.
 // ctx: Pulumi context.
 // sshIPv4: The IPv4 address of the server to connect to via SSH.
 // privateKeyPem: The private key in PEM format to use for SSH authentication.
 // ntfyConfig: Ntfy configuration.
 // mailConfig: Mail configuration.
 // dnsConfig: DNS configuration.
+// serverConfig: Server configuration.
+// users: The provisioned Ntfy users.
 // dependsOn: List of Pulumi resources that this installation depends on.
 func Install(ctx *pulumi.Context,
 	sshIPv4 pulumi.StringOutput,
@@ -26,8 +33,10 @@ func Install(ctx *pulumi.Context,
 	ntfyConfig *ntfyConf.Config,
 	mailConfig *mailConf.Config,
 	dnsConfig *dns.Config,
+	serverConfig *server.Config,
+	users []*ntfyModel.User,
 	dependsOn pulumi.ResourceOrInvokeOption,
-) error {
+) (pulumi.Output, pulumi.MapOutput, error) {
 	conn := &remote.ConnectionArgs{
 		Host:       sshIPv4,
 		PrivateKey: privateKeyPem,
@@ -36,14 +45,14 @@ func Install(ctx *pulumi.Context,
 
 	dnsErr := createDNSRecords(ctx, mailConfig, dnsConfig, ntfyConfig)
 	if dnsErr != nil {
-		return dnsErr
+		return nil, pulumi.MapOutput{}, dnsErr
 	}
 
 	opts := []pulumi.ResourceOption{dependsOn}
 
 	opts, prepErr := install.Prepare(ctx, "ntfy", conn, opts...)
 	if prepErr != nil {
-		return prepErr
+		return nil, pulumi.MapOutput{}, prepErr
 	}
 
 	dockerCompose, _ := template.Render("./assets/ntfy/docker-compose.yml.j2", map[string]any{
@@ -54,33 +63,52 @@ func Install(ctx *pulumi.Context,
 		"ntfy",
 		pulumi.String(dockerCompose),
 		false,
+		serverConfig,
 		conn,
 		opts...)
 	if dcErr != nil {
-		return dcErr
+		return nil, pulumi.MapOutput{}, dcErr
+	}
+
+	opts, verifyImagesHash, viErr := install.VerifyImages(
+		ctx,
+		"ntfy",
+		serverConfig,
+		conn,
+		opts...)
+	if viErr != nil {
+		return nil, pulumi.MapOutput{}, viErr
 	}
 
 	configFileCopy, configFileHash := createConfig(
 		ctx,
 		conn,
 		ntfyConfig,
+		users,
 		opts...)
 
-	_, cronErr := install.Cron(ctx, "ntfy", conn, opts...)
+	_, cronErr := install.Cron(ctx, "ntfy", nil, conn, opts...)
 	if cronErr != nil {
-		return cronErr
+		return nil, pulumi.MapOutput{}, cronErr
 	}
 
 	opts, systemdServiceHash, shErr := install.SystemDService(ctx, "ntfy", conn, opts...)
 	if shErr != nil {
-		return shErr
+		return nil, pulumi.MapOutput{}, shErr
 	}
 
+	opts, healthcheckHash, hcErr := install.HealthcheckScript(ctx, "ntfy", conn, opts...)
+	if hcErr != nil {
+		return nil, pulumi.MapOutput{}, hcErr
+	}
+	healthcheckURL := fmt.Sprintf("https://%s/v1/health", *ntfyConfig.Domain.Name)
+
 	ntfyVersion := install.Version("./outputs/ntfy_docker-compose.yml", "ntfy", dockerComposeHash)
 
 	installFn, _ := ntfyVersion.ApplyT(func(version string) string {
 		ic, _ := template.Render("./assets/ntfy/install.sh.j2", map[string]any{
-			"version": version,
+			"version":     version,
+			"healthcheck": install.HealthcheckCommand("ntfy", healthcheckURL, ""),
 			"bucket": map[string]string{
 				"id":   config.BackupBucketID,
 				"path": config.BackupBucketPath,
@@ -88,6 +116,14 @@ func Install(ctx *pulumi.Context,
 		})
 		return ic
 	}).(pulumi.StringOutput)
+	installTriggers := pulumi.Array{
+		pulumi.String(*systemdServiceHash),
+		pulumi.String(*healthcheckHash),
+		dockerComposeHash,
+		configFileHash,
+		verifyImagesHash,
+		ntfyVersion,
+	}
 	installTask := pulumi.All(configFileCopy, dockerComposeCopy).
 		ApplyT(func(args []any) pulumi.ResourceOption {
 			configCopy, _ := args[0].(pulumi.ResourceOption)
@@ -97,25 +133,22 @@ func Install(ctx *pulumi.Context,
 				ctx,
 				"remote-command-install-ntfy",
 				&remote.CommandArgs{
-					Create: installFn,
-					Update: installFn,
-					Triggers: pulumi.Array{
-						pulumi.String(*systemdServiceHash),
-						dockerComposeHash,
-						configFileHash,
-						ntfyVersion,
-					},
+					Create:     installFn,
+					Update:     installFn,
+					Triggers:   installTriggers,
 					Connection: conn,
 				},
 				append(opts, configCopy, dockerCopy)...)
 			return pulumi.DependsOn([]pulumi.Resource{cmd})
 		})
 
-	installTask.ApplyT(func(installT any) error {
+	healthTask := installTask.ApplyT(func(installT any) pulumi.ResourceOption {
 		installer, _ := installT.(pulumi.ResourceOption)
-		install.Postinstall(ctx, "ntfy", pulumi.Array{}, conn, append(opts, installer)...)
-		return nil
+		postinstallOpts := install.Postinstall(ctx, "ntfy", pulumi.Array{}, conn, append(opts, installer)...)
+		return pulumi.Composite(
+			install.Healthcheck(ctx, "ntfy", healthcheckURL, "", installTriggers, conn, postinstallOpts...)...,
+		)
 	})
 
-	return nil
+	return healthTask, install.Images("./outputs/ntfy_docker-compose.yml", dockerComposeHash), nil
 }
@@ -0,0 +1,70 @@
+package ntfy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
+)
+
+// passwordLength is the length of an Ntfy user password.
+const passwordLength = 32
+
+// tokenLength is the length of an Ntfy access token without its 'tk_' prefix.
+const tokenLength = 29
+
+// CreateUser creates an Ntfy user with read-write access to a topic, and stores its credentials in Vault.
+// ctx: Pulumi context.
+// name: The name of the user.
+// topic: The topic the user can access.
+// secretKey: The Vault key to store the credentials at.
+func CreateUser(ctx *pulumi.Context, name string, topic string, secretKey string) (*ntfyModel.User, error) {
+	password, pErr := random.NewRandomPassword(ctx, fmt.Sprintf("password-ntfy-%s", name), &random.RandomPasswordArgs{
+		Length:  pulumi.Int(passwordLength),
+		Special: pulumi.Bool(false),
+	})
+	if pErr != nil {
+		return nil, pErr
+	}
+	token, tErr := random.NewRandomPassword(ctx, fmt.Sprintf("token-ntfy-%s", name), &random.RandomPasswordArgs{
+		Length:  pulumi.Int(tokenLength),
+		Special: pulumi.Bool(false),
+		Upper:   pulumi.Bool(false),
+	})
+	if tErr != nil {
+		return nil, tErr
+	}
+	accessToken := pulumi.Sprintf("tk_%s", token.Result)
+
+	credentials, _ := pulumi.All(password.Result, accessToken).ApplyT(func(args []any) string {
+		pw, _ := args[0].(string)
+		tk, _ := args[1].(string)
+		value, _ := json.Marshal(map[string]string{
+			"username": name,
+			"password": pw,
+			"token":    tk,
+			"topic":    topic,
+		})
+		return string(value)
+	}).(pulumi.StringOutput)
+	_, sErr := secret.Create(ctx, &secret.CreateOptions{
+		Path:  config.GlobalName,
+		Key:   secretKey,
+		Value: credentials,
+	})
+	if sErr != nil {
+		return nil, sErr
+	}
+
+	return &ntfyModel.User{
+		Name:         name,
+		PasswordHash: password.BcryptHash,
+		Topic:        topic,
+		Token:        accessToken,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/ntfy"
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	ntfyConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/ntfy"
+	ntfyModel "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/ntfy"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+)
+
+// probeNtfyUser is the name of the Ntfy user publishing the probe outcome.
+const probeNtfyUser = "mail-probe"
+
+// CreateNtfyUser creates the Ntfy user publishing the probe outcome, and stores its credentials in Vault.
+// It returns nil if the probe outcome isn't pushed to Ntfy.
+// ctx: Pulumi context.
+// mailConfig: Mail configuration.
+func CreateNtfyUser(ctx *pulumi.Context, mailConfig *mailConf.Config) (*ntfyModel.User, error) {
+	if !mail.ProbeNtfyEnabled(mailConfig) {
+		return nil, nil
+	}
+	return ntfy.CreateUser(ctx, probeNtfyUser, mail.ProbeNtfyTopic(mailConfig), "mail-probe-ntfy")
+}
+
+// Run sends probe messages through the mail flow and verifies their delivery.
+// The probe runs when it is created, when its configuration changes, or when the run identifier changes.
+// It returns a Pulumi Output of the probe outcome, or nil if the probe is disabled.
+// ctx: Pulumi context.
+// sshIPv4: The IPv4 address of the server to connect to via SSH.
+// privateKeyPem: The private key in PEM format to use for SSH authentication.
+// mailConfig: Mail configuration.
+// ntfyConfig: Ntfy configuration.
+// ntfyUser: The Ntfy user publishing the probe outcome (optional).
+// mailboxPasswords: The passwords of the mailboxes managed in mailcow; the probe mailbox password is enforced.
+// dependsOn: List of Pulumi resources that the probe depends on.
+func Run(ctx *pulumi.Context,
+	sshIPv4 pulumi.StringOutput,
+	privateKeyPem pulumi.StringOutput,
+	mailConfig *mailConf.Config,
+	ntfyConfig *ntfyConf.Config,
+	ntfyUser *ntfyModel.User,
+	mailboxPasswords pulumi.MapOutput,
+	dependsOn pulumi.ResourceOrInvokeOption,
+) (pulumi.Output, error) {
+	if !mail.ProbeEnabled(mailConfig) {
+		return nil, nil
+	}
+
+	conn := &remote.ConnectionArgs{
+		Host:       sshIPv4,
+		PrivateKey: privateKeyPem,
+		User:       pulumi.String("root"),
+	}
+
+	opts := []pulumi.ResourceOption{dependsOn}
+
+	opts, prepErr := install.Prepare(ctx, "probe", conn, opts...)
+	if prepErr != nil {
+		return nil, prepErr
+	}
+
+	probeHash, phErr := file.Hash("./assets/probe/probe.py")
+	if phErr != nil {
+		return nil, phErr
+	}
+	probeCopy, pcErr := remote.NewCopyToRemote(
+		ctx,
+		"remote-copy-probe-py",
+		&remote.CopyToRemoteArgs{
+			Source:     pulumi.NewFileAsset("./assets/probe/probe.py"),
+			RemotePath: pulumi.String("/opt/probe/probe.py"),
+			Triggers:   pulumi.Array{pulumi.String(*probeHash)},
+			Connection: conn,
+		},
+		opts...)
+	if pcErr != nil {
+		return nil, pcErr
+	}
+
+	probe, prErr := remote.NewCommand(ctx, "remote-command-probe", &remote.CommandArgs{
+		Create: pulumi.String("python3 /opt/probe/probe.py"),
+		Update: pulumi.String("python3 /opt/probe/probe.py"),
+		Stdin:  pulumi.ToSecret(probeConfig(mailConfig, ntfyConfig, ntfyUser, mailboxPasswords)).(pulumi.StringOutput),
+		// the probe runs again if the script or the run identifier changes, keeping previews free of changes
+		Triggers:   pulumi.Array{pulumi.String(*probeHash), pulumi.String(mail.ProbeRun(mailConfig))},
+		Connection: conn,
+	}, append(opts, pulumi.DependsOn([]pulumi.Resource{probeCopy}))...)
+	if prErr != nil {
+		return nil, prErr
+	}
+
+	outcome := probe.Stdout.ApplyT(func(stdout string) (map[string]any, error) {
+		var result map[string]any
+		if uErr := json.Unmarshal([]byte(stdout), &result); uErr != nil {
+			return nil, fmt.Errorf("failed to parse the probe outcome: %w", uErr)
+		}
+		return result, nil
+	}).(pulumi.MapOutput)
+	return outcome, nil
+}
+
+// probeConfig returns the configuration of the probe script as JSON.
+// mailConfig: Mail configuration.
+// ntfyConfig: Ntfy configuration.
+// ntfyUser: The Ntfy user publishing the probe outcome (optional).
+// mailboxPasswords: The initial passwords of the mailboxes managed in mailcow.
+func probeConfig(
+	mailConfig *mailConf.Config,
+	ntfyConfig *ntfyConf.Config,
+	ntfyUser *ntfyModel.User,
+	mailboxPasswords pulumi.MapOutput,
+) pulumi.StringOutput {
+	token := pulumi.String("").ToStringOutput()
+	if ntfyUser != nil {
+		token = ntfyUser.Token
+	}
+
+	config, _ := pulumi.All(mailboxPasswords, token).ApplyT(func(args []any) string {
+		passwords, _ := args[0].(map[string]any)
+		tk, _ := args[1].(string)
+
+		mailbox := mail.ProbeMailbox(mailConfig)
+		password, _ := passwords[mailbox].(string)
+		value := map[string]any{
+			"mailname": mail.Mailname(*mailConfig.Main.Name),
+			"mailbox":  mailbox,
+			"password": password,
+			"alias":    mail.ProbeAlias(mailConfig),
+		}
+		if ntfyUser != nil {
+			value["ntfy"] = map[string]string{
+				"url":   fmt.Sprintf("https://%s/", *ntfyConfig.Domain.Name),
+				"topic": ntfyUser.Topic,
+				"token": tk,
+			}
+		}
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}).(pulumi.StringOutput)
+	return config
+}
@@ -0,0 +1,155 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/image"
+)
+
+// defaultTimeout is the timeout of a single registry request.
+const defaultTimeout = 30 * time.Second
+
+// dockerHubRegistry is the registry name of Docker Hub in image references.
+const dockerHubRegistry = "docker.io"
+
+// dockerHubHost is the host serving the registry API of Docker Hub.
+const dockerHubHost = "registry-1.docker.io"
+
+// defaultTag is the tag of image references without a tag.
+const defaultTag = "latest"
+
+// manifestMediaTypes are the accepted manifest media types; indexes are preferred to keep the digest multi-platform.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Digest resolves the digest of an image reference with the registry API.
+// Public images are resolved anonymously, requesting a pull token if the registry asks for one.
+// ctx: The context of the requests.
+// ref: The image reference to resolve.
+func Digest(ctx context.Context, ref image.Reference) (string, error) {
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	host := ref.Registry
+	if host == dockerHubRegistry {
+		host = dockerHubHost
+	}
+	tag := ref.Tag
+	if tag == "" {
+		tag = defaultTag
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, ref.Repository, tag)
+
+	client := &http.Client{Timeout: defaultTimeout}
+
+	resp, rErr := headManifest(ctx, client, manifestURL, "")
+	if rErr != nil {
+		return "", rErr
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, tErr := pullToken(ctx, client, resp.Header.Get("WWW-Authenticate"))
+		if tErr != nil {
+			return "", fmt.Errorf("failed to authenticate for %s/%s: %w", ref.Registry, ref.Repository, tErr)
+		}
+		resp, rErr = headManifest(ctx, client, manifestURL, token)
+		if rErr != nil {
+			return "", rErr
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve %s/%s:%s: %s", ref.Registry, ref.Repository, tag, resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", fmt.Errorf("registry returned no digest for %s/%s:%s", ref.Registry, ref.Repository, tag)
+	}
+	return digest, nil
+}
+
+// headManifest requests the headers of a manifest.
+// ctx: The context of the request.
+// client: The HTTP client.
+// manifestURL: The URL of the manifest.
+// token: The bearer token (optional).
+func headManifest(ctx context.Context, client *http.Client, manifestURL string, token string) (*http.Response, error) {
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, doErr := client.Do(req)
+	if doErr != nil {
+		return nil, fmt.Errorf("failed to request %s: %w", manifestURL, doErr)
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// pullToken requests an anonymous pull token for the bearer challenge of a registry.
+// ctx: The context of the request.
+// client: The HTTP client.
+// challenge: The WWW-Authenticate header (e.g. 'Bearer realm="...",service="...",scope="..."').
+func pullToken(ctx context.Context, client *http.Client, challenge string) (string, error) {
+	params, ok := strings.CutPrefix(challenge, "Bearer ")
+	if !ok {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	query := url.Values{}
+	realm := ""
+	for param := range strings.SplitSeq(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		value = strings.Trim(value, `"`)
+		if key == "realm" {
+			realm = value
+			continue
+		}
+		query.Set(key, value)
+	}
+	if realm == "" {
+		return "", fmt.Errorf("authentication challenge %q has no realm", challenge)
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if reqErr != nil {
+		return "", reqErr
+	}
+	resp, doErr := client.Do(req)
+	if doErr != nil {
+		return "", doErr
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if dErr := json.NewDecoder(resp.Body).Decode(&body); dErr != nil {
+		return "", dErr
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
@@ -3,17 +3,15 @@ package simplelogin
 import (
 	"fmt"
 
-	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws/iam"
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
-	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	scalewayConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/scaleway"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/file"
-	"github.com/muhlba91/pulumi-shared-library/pkg/lib/aws/s3/bucket"
+	simpleloginUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/simplelogin"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/random"
-	"github.com/muhlba91/pulumi-shared-library/pkg/util/aws/region"
 	fileUtil "github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 )
@@ -33,70 +31,60 @@ const postgresPort = 5432
 // postgresqlPassword: The password for the PostgreSQL user.
 // simpleloginConfig: Configuration for SimpleLogin installation.
 // serverConfig: Configuration of the server where SimpleLogin is installed.
+// scalewayConfig: Configuration for Scaleway.
 // opts: Additional Pulumi resource options.
 func createConfig(ctx *pulumi.Context,
 	conn *remote.ConnectionArgs,
 	postgresqlPassword pulumi.StringOutput,
 	simpleloginConfig *simpleloginConf.Config,
 	serverConfig *server.Config,
+	scalewayConfig *scalewayConf.Config,
 	opts ...pulumi.ResourceOption,
-) (pulumi.Output, pulumi.StringOutput) {
+) (pulumi.Output, pulumi.StringOutput, error) {
 	flaskSecret, _ := random.CreatePassword(ctx, "password-simplelogin-flask-secret", &random.PasswordOptions{
 		Length:  flaskSecretLength,
 		Special: false,
 	})
 
-	s3Bucket, _ := bucket.Create(ctx, &bucket.CreateOptions{
-		Name:   fmt.Sprintf("%s-simplelogin", config.GlobalName),
-		Labels: config.CommonLabels(),
-	})
-	key := s3Bucket.Arn.ApplyT(func(arn string) iam.AccessKeyOutput {
-		k, _ := createAWSUser(ctx, arn)
-		return *k
-	})
+	storage, stErr := createStorage(ctx, simpleloginConfig, scalewayConfig)
+	if stErr != nil {
+		return nil, pulumi.StringOutput{}, stErr
+	}
 
-	envFile, _ := pulumi.All(postgresqlPassword, flaskSecret.Password, s3Bucket.Bucket, key).ApplyT(func(args []any) pulumi.StringOutput {
+	envFile, _ := pulumi.All(postgresqlPassword, flaskSecret.Password, storage).ApplyT(func(args []any) string {
 		postgresqlPasword, _ := args[0].(string)
 		flaskSecretPassword, _ := args[1].(string)
-		bucketName, _ := args[2].(string)
-		accessKey, _ := args[3].(*iam.AccessKey)
-
-		eFile, _ := pulumi.All(accessKey.ID(), accessKey.Secret).ApplyT(func(akArgs []any) string {
-			accessKeyID, _ := akArgs[0].(pulumi.ID)
-			secretAccessKey, _ := akArgs[1].(string)
-			env, _ := template.Render("./assets/simplelogin/env.j2", map[string]any{
-				"flaskSecret": flaskSecretPassword,
-				"db": map[string]any{
-					"uri":      fmt.Sprintf("postgresql://%s:%s@%s:%d/%s", databaseName, postgresqlPasword, postgresAddress, postgresPort, databaseName), //nolint:nosprintfhostport // we need the full address here
-					"host":     postgresAddress,
-					"port":     postgresPort,
-					"database": databaseName,
-					"user":     databaseName,
-					//nolint:goconst // intentional duplication of "password" key for better structure
-					"password": postgresqlPasword,
-				},
-				"aws": map[string]any{
-					"bucket":          bucketName,
-					"region":          region.GetOrDefault(ctx, &config.AWSDefaultRegion),
-					"accessKeyId":     accessKeyID,
-					"secretAccessKey": secretAccessKey,
-				},
-				"oidc": map[string]any{
-					"wellKnownUrl": simpleloginConfig.OIDC.WellKnownURL,
-					"clientId":     simpleloginConfig.OIDC.ClientID,
-					"clientSecret": simpleloginConfig.OIDC.ClientSecret,
-				},
-				//nolint:goconst // intentional duplication of "domain" key for better structure in the template
-				"domain": simpleloginConfig.Domain,
-				"email": map[string]any{
-					"domain": simpleloginConfig.Mail.Domain,
-					"mx":     simpleloginConfig.Mail.MX,
-					"relay":  serverConfig.IPv4,
-				},
-			})
-			return env
-		}).(pulumi.StringOutput)
-		return eFile
+		storageValues, _ := args[2].(map[string]any)
+		env, _ := template.Render("./assets/simplelogin/env.j2", map[string]any{
+			"flaskSecret": flaskSecretPassword,
+			"db": map[string]any{
+				"uri":      fmt.Sprintf("postgresql://%s:%s@%s:%d/%s", databaseName, postgresqlPasword, postgresAddress, postgresPort, databaseName), //nolint:nosprintfhostport // we need the full address here
+				"host":     postgresAddress,
+				"port":     postgresPort,
+				"database": databaseName,
+				"user":     databaseName,
+				//nolint:goconst // intentional duplication of "password" key for better structure
+				"password": postgresqlPasword,
+			},
+			"storage": storageValues,
+			"oidc": map[string]any{
+				"wellKnownUrl": simpleloginConfig.OIDC.WellKnownURL,
+				"clientId":     simpleloginConfig.OIDC.ClientID,
+				"clientSecret": simpleloginConfig.OIDC.ClientSecret,
+			},
+			"settings": simpleloginUtil.Settings(simpleloginConfig),
+			//nolint:goconst // intentional duplication of "domain" key for better structure in the template
+			"domain": simpleloginConfig.Domain,
+			"email": map[string]any{
+				"domain":              *simpleloginConfig.Mail.Domain,
+				"relayDomain":         simpleloginUtil.RelayDomain(simpleloginConfig),
+				"otherAliasDomains":   simpleloginUtil.OtherAliasDomains(simpleloginConfig),
+				"premiumAliasDomains": simpleloginUtil.PremiumAliasDomains(simpleloginConfig),
+				"mx":                  simpleloginConfig.Mail.MX,
+				"relay":               serverConfig.IPv4,
+			},
+		})
+		return env
 	}).(pulumi.StringOutput)
 	envFileHash, _ := file.WriteAndUpload(ctx, "simplelogin_env", envFile).
 		ApplyT(func(_ any) string {
@@ -117,5 +105,5 @@ func createConfig(ctx *pulumi.Context,
 		return pulumi.DependsOn([]pulumi.Resource{cmd})
 	})
 
-	return envFileCopy, envFileHash
+	return envFileCopy, envFileHash, nil
 }
@@ -0,0 +1,77 @@
+package simplelogin
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/api"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object/resource"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+	simpleloginUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/simplelogin"
+)
+
+// relayDomainQuota is the quota in MiB of the relay domains, which don't hold any mailboxes.
+const relayDomainQuota = 1
+
+// configureMailcow routes the SimpleLogin alias domains through mailcow's Postfix to the SimpleLogin email handler.
+// Every alias domain is managed as a relay domain with a transport map entry to the email handler;
+// its recipients are validated against the maps exported from SimpleLogin by the recipients cron job.
+// ctx: Pulumi context.
+// mailcowAPI: The connection to the mailcow API.
+// simpleloginConfig: Configuration for SimpleLogin installation.
+// serverConfig: Configuration of the server where SimpleLogin is installed.
+func configureMailcow(ctx *pulumi.Context,
+	mailcowAPI *resource.Connection,
+	simpleloginConfig *simpleloginConf.Config,
+	serverConfig *server.Config,
+) error {
+	nexthop := simpleloginUtil.HandlerNexthop(*serverConfig.IPv4)
+	for _, domain := range simpleloginUtil.AliasDomainNames(simpleloginConfig) {
+		relayDomain, dErr := resource.Manage(ctx, &object.Object{
+			Kind: object.KindDomain,
+			Name: domain,
+			Domain: &api.DomainAttributes{
+				Description:        "SimpleLogin alias domain",
+				Quota:              relayDomainQuota,
+				DefaultQuota:       relayDomainQuota,
+				MaxQuota:           relayDomainQuota,
+				RateLimitFrame:     "s",
+				Active:             "1",
+				BackupMX:           "1",
+				RelayAllRecipients: "0",
+				RelayUnknownOnly:   "0",
+			},
+		}, mailcowAPI, nil)
+		if dErr != nil {
+			return dErr
+		}
+
+		_, tErr := resource.Manage(ctx, &object.Object{
+			Kind:   object.KindTransport,
+			Name:   domain,
+			Target: nexthop,
+			Active: "1",
+		}, mailcowAPI, nil, pulumi.DependsOn([]pulumi.Resource{relayDomain}))
+		if tErr != nil {
+			return tErr
+		}
+	}
+	return nil
+}
+
+// recipientMapValues returns the values rendering the recipients cron job.
+// simpleloginConfig: Configuration for SimpleLogin installation.
+func recipientMapValues(simpleloginConfig *simpleloginConf.Config) map[string]string {
+	domains := []string{}
+	for _, domain := range simpleloginUtil.AliasDomainNames(simpleloginConfig) {
+		domains = append(domains, regexp.QuoteMeta(strings.ToLower(domain)))
+	}
+	return map[string]string{
+		"aliasDomains": strings.Join(domains, "|"),
+		"relayDomain":  regexp.QuoteMeta(strings.ToLower(simpleloginUtil.RelayDomain(simpleloginConfig))),
+	}
+}
@@ -5,17 +5,23 @@ import (
 	"fmt"
 
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/hetzner/snapshot"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/mailcow/object/resource"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
 	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	scalewayConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/scaleway"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/dkim"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
+	simpleloginUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/simplelogin"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/random"
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/vault/secret"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
 )
@@ -28,23 +34,31 @@ const postgresPasswordLength = 32
 
 // Install SimpleLogin on the remote server via SSH and create necessary resources.
 // ctx: Pulumi context.
+// serverID: The identifier of the server.
 // sshIPv4: The IPv4 address of the server to connect to via SSH.
 // privateKeyPem: The private key in PEM format to use for SSH authentication.
 // postgresqlUsers: Map of PostgreSQL users needed for SimpleLogin.
 // simpleloginConfig: Configuration for SimpleLogin installation.
 // serverConfig: Configuration of the server where SimpleLogin is installed.
+// mailConfig: Configuration for mail services.
+// dnsConfig: Configuration for DNS services.
+// scalewayConfig: Configuration for Scaleway.
+// mailcowAPI: The connection to the mailcow API.
 // dependsOn: List of Pulumi resources that this installation depends on.
 //
 //nolint:funlen // this is a long function, but it's necessary for the installation process
 func Install(ctx *pulumi.Context,
+	serverID pulumi.IntOutput,
 	sshIPv4 pulumi.StringOutput,
 	privateKeyPem pulumi.StringOutput,
 	simpleloginConfig *simpleloginConf.Config,
 	serverConfig *server.Config,
 	mailConfig *mailConf.Config,
 	dnsConfig *dns.Config,
+	scalewayConfig *scalewayConf.Config,
+	mailcowAPI *resource.Connection,
 	dependsOn pulumi.ResourceOrInvokeOption,
-) (*dkim.Data, error) {
+) (*dkim.Data, *hcloud.Snapshot, pulumi.MapOutput, error) {
 	conn := &remote.ConnectionArgs{
 		Host:       sshIPv4,
 		PrivateKey: privateKeyPem,
@@ -55,7 +69,7 @@ func Install(ctx *pulumi.Context,
 
 	opts, prepErr := install.Prepare(ctx, "simplelogin", conn, opts...)
 	if prepErr != nil {
-		return nil, prepErr
+		return nil, nil, pulumi.MapOutput{}, prepErr
 	}
 
 	// postgres password
@@ -64,7 +78,16 @@ func Install(ctx *pulumi.Context,
 	dockerCompose, _ := postgresqlPassword.ApplyT(func(pgPass string) string {
 		tpl, _ := template.Render("./assets/simplelogin/docker-compose.yml.j2", map[string]any{
 			//nolint:goconst // intentional duplication of "domain" key for better structure in the template
-			"domain": simpleloginConfig.Domain,
+			"domain":       simpleloginConfig.Domain,
+			"localStorage": simpleloginUtil.StorageBackend(simpleloginConfig) == simpleloginUtil.StorageLocal,
+			"web":          simpleloginUtil.WebSettings(simpleloginConfig, defaults.GetOrDefault(serverConfig.Type, "")),
+			"redis": map[string]any{
+				"persistence": simpleloginUtil.RedisPersistence(simpleloginConfig),
+			},
+			"pitr": map[string]any{
+				"enabled":        simpleloginUtil.PITREnabled(simpleloginConfig),
+				"archiveTimeout": simpleloginUtil.PITRArchiveTimeout(simpleloginConfig),
+			},
 			"db": map[string]any{
 				"database": databaseName,
 				"user":     databaseName,
@@ -79,39 +102,65 @@ func Install(ctx *pulumi.Context,
 		"simplelogin",
 		dockerCompose,
 		false,
+		serverConfig,
 		conn,
 		opts...)
 	if dcErr != nil {
-		return nil, dcErr
+		return nil, nil, pulumi.MapOutput{}, dcErr
+	}
+
+	opts, verifyImagesHash, viErr := install.VerifyImages(
+		ctx,
+		"simplelogin",
+		serverConfig,
+		conn,
+		opts...)
+	if viErr != nil {
+		return nil, nil, pulumi.MapOutput{}, viErr
 	}
 
 	dkimKey, dkimKeyCopy, dkErr := createDKIMConfig(ctx, conn, simpleloginConfig, mailConfig, dnsConfig, opts...)
 	if dkErr != nil {
-		return nil, dkErr
+		return nil, nil, pulumi.MapOutput{}, dkErr
 	}
-	envFileCopy, envFileHash := createConfig(
+	envFileCopy, envFileHash, cfgErr := createConfig(
 		ctx,
 		conn,
 		postgresqlPassword,
 		simpleloginConfig,
 		serverConfig,
+		scalewayConfig,
 		opts...)
+	if cfgErr != nil {
+		return nil, nil, pulumi.MapOutput{}, cfgErr
+	}
 
-	_, cronErr := install.Cron(ctx, "simplelogin", conn, opts...)
+	healthcheckURL := fmt.Sprintf("https://%s/", *simpleloginConfig.Domain)
+	_, cronErr := install.Cron(ctx, "simplelogin", map[string]any{
+		"retentionDays": simpleloginUtil.BackupRetentionDays(simpleloginConfig),
+		"pitr":          simpleloginUtil.PITREnabled(simpleloginConfig),
+		"healthcheck":   install.HealthcheckCommand("simplelogin", healthcheckURL, ""),
+		"recipients":    recipientMapValues(simpleloginConfig),
+	}, conn, opts...)
 	if cronErr != nil {
-		return nil, cronErr
+		return nil, nil, pulumi.MapOutput{}, cronErr
 	}
 
 	opts, systemdServiceHash, shErr := install.SystemDService(ctx, "simplelogin", conn, opts...)
 	if shErr != nil {
-		return nil, shErr
+		return nil, nil, pulumi.MapOutput{}, shErr
+	}
+
+	opts, healthcheckHash, hcErr := install.HealthcheckScript(ctx, "simplelogin", conn, opts...)
+	if hcErr != nil {
+		return nil, nil, pulumi.MapOutput{}, hcErr
 	}
 
 	simpleloginVersion := install.Version("./outputs/simplelogin_docker-compose.yml", "app", dockerComposeHash)
 
 	initShHash, ishErr := file.Hash("./assets/simplelogin/init.sh")
 	if ishErr != nil {
-		return nil, ishErr
+		return nil, nil, pulumi.MapOutput{}, ishErr
 	}
 	initShCopy, ishcErr := remote.NewCopyToRemote(
 		ctx,
@@ -124,16 +173,62 @@ func Install(ctx *pulumi.Context,
 		},
 		opts...)
 	if ishcErr != nil {
-		return nil, ishcErr
+		return nil, nil, pulumi.MapOutput{}, ishcErr
+	}
+
+	// the data directory of postgres is migrated if the major version of the image changes
+	postgresMajorVersion := install.MajorVersion(
+		"./outputs/simplelogin_docker-compose.yml",
+		"postgres",
+		dockerComposeHash,
+	)
+	postgresUpgradeHash, puhErr := file.Hash("./assets/simplelogin/postgres-upgrade.sh")
+	if puhErr != nil {
+		return nil, nil, pulumi.MapOutput{}, puhErr
+	}
+	postgresUpgradeCopy, pucErr := remote.NewCopyToRemote(
+		ctx,
+		"remote-copy-simplelogin-postgres-upgrade-sh",
+		&remote.CopyToRemoteArgs{
+			Source:     pulumi.NewFileAsset("./assets/simplelogin/postgres-upgrade.sh"),
+			RemotePath: pulumi.String("/opt/simplelogin/postgres-upgrade.sh"),
+			Triggers:   pulumi.Array{pulumi.String(*postgresUpgradeHash)},
+			Connection: conn,
+		},
+		opts...)
+	if pucErr != nil {
+		return nil, nil, pulumi.MapOutput{}, pucErr
 	}
 
-	installFn, _ := simpleloginVersion.ApplyT(func(version string) string {
+	installFn, _ := pulumi.All(simpleloginVersion, postgresMajorVersion).ApplyT(func(args []any) string {
+		version, _ := args[0].(string)
+		postgresMajor, _ := args[1].(string)
 		ic, _ := template.Render("./assets/simplelogin/install.sh.j2", map[string]any{
-			"version": version,
+			"version":       version,
+			"postgresMajor": postgresMajor,
+			"healthcheck":   install.HealthcheckCommand("simplelogin", healthcheckURL, ""),
 		})
 		return ic
 	}).(pulumi.StringOutput)
-	_ = pulumi.All(dkimKeyCopy, envFileCopy, initShCopy, dockerComposeCopy).
+	installTriggers := pulumi.Array{
+		pulumi.String(*systemdServiceHash),
+		dockerComposeHash,
+		envFileHash,
+		pulumi.String(*initShHash),
+		pulumi.String(*postgresUpgradeHash),
+		pulumi.String(*healthcheckHash),
+		verifyImagesHash,
+		simpleloginVersion,
+		postgresMajorVersion,
+	}
+
+	// a server snapshot is taken before every change of the installation, which may migrate the database
+	installSnapshot, snErr := snapshot.Create(ctx, "simplelogin", serverID, installTriggers, serverConfig, opts...)
+	if snErr != nil {
+		return nil, nil, pulumi.MapOutput{}, snErr
+	}
+
+	installTask := pulumi.All(dkimKeyCopy, envFileCopy, initShCopy, dockerComposeCopy).
 		ApplyT(func(args []any) pulumi.ResourceOption {
 			dkimCopy, _ := args[0].(pulumi.ResourceOption)
 			envCopy, _ := args[1].(pulumi.ResourceOption)
@@ -144,22 +239,29 @@ func Install(ctx *pulumi.Context,
 				ctx,
 				"remote-command-install-simplelogin",
 				&remote.CommandArgs{
-					Create: installFn,
-					Update: installFn,
-					Triggers: pulumi.Array{
-						pulumi.String(*systemdServiceHash),
-						dockerComposeHash,
-						envFileHash,
-						pulumi.String(*initShHash),
-						simpleloginVersion,
-					},
+					Create:     installFn,
+					Update:     installFn,
+					Triggers:   installTriggers,
 					Connection: conn,
 				},
-				append(opts, dkimCopy, envCopy, initCopy, dockerCopy)...)
+				append(opts, dkimCopy, envCopy, initCopy, dockerCopy, pulumi.DependsOn([]pulumi.Resource{installSnapshot, postgresUpgradeCopy}))...)
 			return pulumi.DependsOn([]pulumi.Resource{cmd})
 		})
 
-	return dkimKey, nil
+	installTask.ApplyT(func(installT any) error {
+		installer, _ := installT.(pulumi.ResourceOption)
+		install.Healthcheck(ctx, "simplelogin", healthcheckURL, "", installTriggers, conn, append(opts, installer)...)
+		return nil
+	})
+
+	mcErr := configureMailcow(ctx, mailcowAPI, simpleloginConfig, serverConfig)
+	if mcErr != nil {
+		return nil, nil, pulumi.MapOutput{}, mcErr
+	}
+
+	images := install.Images("./outputs/simplelogin_docker-compose.yml", dockerComposeHash)
+
+	return dkimKey, installSnapshot, images, nil
 }
 
 // createPostgresPassword generates a random password for the PostgreSQL user and stores it in a secret.
@@ -2,15 +2,23 @@ package simplelogin
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/muhlba91/pulumi-shared-library/pkg/lib/google/dns/record"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/dkim"
 	dnsConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
 	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
 	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+	simpleloginUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/simplelogin"
+)
+
+const (
+	// aliasDomainMXPriority is the priority of the MX record of the SimpleLogin alias domains.
+	aliasDomainMXPriority = 10
+	// aliasDomainDMARCRecord is the DMARC policy of the SimpleLogin alias domains, which only send signed mail.
+	aliasDomainDMARCRecord = "v=DMARC1; p=quarantine; pct=100; adkim=s; aspf=s"
 )
 
 // createDNSRecords creates DNS records for SimpleLogin based on the provided DNS configuration.
@@ -47,9 +55,7 @@ func createDNSRecords(
 	}
 
 	for _, selector := range dkimSelectors {
-		records, _ := pulumi.Sprintf("v=DKIM1; k=rsa; t=s; s=email; p=%s", dkimPublicKey).ApplyT(func(value string) string {
-			return splitByLength(value, "TXT")
-		}).(pulumi.StringOutput)
+		records := dkim.TXTRecord(dkimPublicKey)
 		_, dkimErr := record.Create(ctx, &record.CreateOptions{
 			Domain:     fmt.Sprintf("%s._domainkey.%s", selector, *simpleloginConfig.Mail.Domain),
 			ZoneID:     zoneID,
@@ -64,30 +70,88 @@ func createDNSRecords(
 		}
 	}
 
+	// the alias domains receive mail through mailcow, which relays it to the SimpleLogin email handler
+	for _, domain := range simpleloginUtil.AliasDomains(simpleloginConfig) {
+		if adErr := createAliasDomainRecords(
+			ctx,
+			domain,
+			dkimPublicKey,
+			dkimSelectors,
+			mainServerDomain,
+			mailConfig,
+			dnsConfig,
+		); adErr != nil {
+			return adErr
+		}
+	}
+
 	return nil
 }
 
-// splitByLength splits a string into chunks and formats the result according to the DNS record type.
-// value: The string to be split.
-// typ: The DNS record type (e.g., "TXT").
-func splitByLength(value string, typ string) string {
-	const maxLen = 200
-	if value == "" {
-		return ""
-	}
+// createAliasDomainRecords creates the DKIM, MX, SPF, and DMARC records of a SimpleLogin alias domain.
+// ctx: The Pulumi context for resource creation.
+// domain: The alias domain.
+// dkimPublicKey: The DKIM public key to be used in DNS records.
+// dkimSelectors: The DKIM selectors to publish the public key for.
+// mainServerDomain: The fully qualified domain of the mail server.
+// mailConfig: The mail configuration containing domain and other settings.
+// dnsConfig: The DNS configuration containing zone and domain information.
+func createAliasDomainRecords(
+	ctx *pulumi.Context,
+	domain *simpleloginConf.AliasDomainConfig,
+	dkimPublicKey pulumi.StringOutput,
+	dkimSelectors []string,
+	mainServerDomain pulumi.StringInput,
+	mailConfig *mailConf.Config,
+	dnsConfig *dnsConf.Config,
+) error {
+	_, zoneID, project := mail.DNSCoreDetails(domain.ZoneID, domain.Project, mailConfig, dnsConfig)
 
-	var parts []string
-	for i := 0; i < len(value); i += maxLen {
-		end := i + maxLen
-		if end > len(value) {
-			end = len(value)
+	for _, selector := range dkimSelectors {
+		_, dkimErr := record.Create(ctx, &record.CreateOptions{
+			Domain:     fmt.Sprintf("%s._domainkey.%s", selector, *domain.Domain),
+			ZoneID:     zoneID,
+			RecordType: "TXT",
+			Records:    pulumi.StringArray{dkim.TXTRecord(dkimPublicKey)},
+			Project:    &project,
+		})
+		if dkimErr != nil {
+			return dkimErr
 		}
-		parts = append(parts, value[i:end])
 	}
 
-	if len(parts) > 1 || typ == "TXT" {
-		return fmt.Sprintf("\"%s\"", strings.Join(parts, "\" \""))
+	_, mxErr := record.Create(ctx, &record.CreateOptions{
+		Domain:     *domain.Domain,
+		ZoneID:     zoneID,
+		RecordType: "MX",
+		Records:    pulumi.StringArray{pulumi.Sprintf("%d %s", aliasDomainMXPriority, mainServerDomain)},
+		Project:    &project,
+	})
+	if mxErr != nil {
+		return mxErr
+	}
+
+	_, spfErr := record.Create(ctx, &record.CreateOptions{
+		Domain:     *domain.Domain,
+		ZoneID:     zoneID,
+		RecordType: "TXT",
+		Records:    pulumi.StringArray{pulumi.String(mail.SPFRecord(mailConfig, *domain.Domain))},
+		Project:    &project,
+	})
+	if spfErr != nil {
+		return spfErr
 	}
 
-	return strings.Join(parts, "")
+	_, dmarcErr := record.Create(ctx, &record.CreateOptions{
+		Domain:     fmt.Sprintf("_dmarc.%s", *domain.Domain),
+		ZoneID:     zoneID,
+		RecordType: "TXT",
+		Records:    pulumi.StringArray{pulumi.String(aliasDomainDMARCRecord)},
+		Project:    &project,
+	})
+	if dmarcErr != nil {
+		return dmarcErr
+	}
+
+	return nil
 }
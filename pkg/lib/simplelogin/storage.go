@@ -0,0 +1,144 @@
+package simplelogin
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/lib/aws/s3/bucket"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/aws/region"
+	slApplication "github.com/muhlba91/pulumi-shared-library/pkg/util/scaleway/iam/application"
+	"github.com/pulumi/pulumi-aws/sdk/v7/go/aws/iam"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumiverse/pulumi-scaleway/sdk/go/scaleway/object"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
+	scalewayConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/scaleway"
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+	scalewayUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/scaleway"
+	simpleloginUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/simplelogin"
+)
+
+// scalewayBucketActions are the actions of SimpleLogin on the objects of its Scaleway bucket.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var scalewayBucketActions = []string{"s3:ListBucket", "s3:GetObject", "s3:PutObject", "s3:DeleteObject"}
+
+// createStorage creates the object storage of the SimpleLogin uploads with credentials scoped to its bucket.
+// The output resolves to the storage values of the env file.
+// ctx: Pulumi context.
+// simpleloginConfig: Configuration for SimpleLogin installation.
+// scalewayConfig: Configuration for Scaleway.
+func createStorage(ctx *pulumi.Context,
+	simpleloginConfig *simpleloginConf.Config,
+	scalewayConfig *scalewayConf.Config,
+) (pulumi.Output, error) {
+	switch simpleloginUtil.StorageBackend(simpleloginConfig) {
+	case simpleloginUtil.StorageScaleway:
+		return createScalewayStorage(ctx, simpleloginConfig, scalewayConfig)
+	case simpleloginUtil.StorageLocal:
+		return pulumi.ToOutput(map[string]any{"backend": simpleloginUtil.StorageLocal}), nil
+	default:
+		return createAWSStorage(ctx, simpleloginConfig)
+	}
+}
+
+// createAWSStorage creates an AWS S3 bucket and a user scoped to it.
+// ctx: Pulumi context.
+// simpleloginConfig: Configuration for SimpleLogin installation.
+func createAWSStorage(ctx *pulumi.Context, simpleloginConfig *simpleloginConf.Config) (pulumi.Output, error) {
+	s3Bucket, bErr := bucket.Create(ctx, &bucket.CreateOptions{
+		Name:   fmt.Sprintf("%s-simplelogin", config.GlobalName),
+		Labels: config.CommonLabels(),
+	})
+	if bErr != nil {
+		return nil, bErr
+	}
+	key := s3Bucket.Arn.ApplyT(func(arn string) iam.AccessKeyOutput {
+		k, _ := createAWSUser(ctx, arn)
+		return *k
+	})
+
+	bucketRegion := region.GetOrDefault(ctx, &config.AWSDefaultRegion)
+	return pulumi.All(s3Bucket.Bucket, key).ApplyT(func(args []any) pulumi.Output {
+		bucketName, _ := args[0].(string)
+		accessKey, _ := args[1].(*iam.AccessKey)
+
+		return pulumi.All(accessKey.ID(), accessKey.Secret).ApplyT(func(akArgs []any) map[string]any {
+			accessKeyID, _ := akArgs[0].(pulumi.ID)
+			secretAccessKey, _ := akArgs[1].(string)
+			return map[string]any{
+				"backend":         simpleloginUtil.StorageAWS,
+				"bucket":          bucketName,
+				"region":          bucketRegion,
+				"endpoint":        simpleloginUtil.StorageEndpoint(simpleloginConfig, bucketRegion),
+				"accessKeyId":     string(accessKeyID),
+				"secretAccessKey": secretAccessKey,
+			}
+		})
+	}), nil
+}
+
+// createScalewayStorage creates a Scaleway Object Storage bucket and an application scoped to it.
+// The application has no IAM policy; its access is granted by the bucket policy only.
+// ctx: Pulumi context.
+// simpleloginConfig: Configuration for SimpleLogin installation.
+// scalewayConfig: Configuration for Scaleway.
+func createScalewayStorage(ctx *pulumi.Context,
+	simpleloginConfig *simpleloginConf.Config,
+	scalewayConfig *scalewayConf.Config,
+) (pulumi.Output, error) {
+	resourceName := fmt.Sprintf("%s-%s-simplelogin", config.GlobalName, config.Environment)
+	bucketRegion := simpleloginUtil.StorageRegion(simpleloginConfig, config.ScalewayDefaultRegion)
+
+	app, aErr := slApplication.CreateApplication(ctx, &slApplication.CreateOptions{
+		Name:             resourceName,
+		DefaultProjectID: pulumi.StringPtrFromPtr(scalewayConfig.Project),
+	})
+	if aErr != nil {
+		return nil, aErr
+	}
+
+	scwBucket, bErr := object.NewBucket(ctx, "scw-object-bucket-simplelogin", &object.BucketArgs{
+		Name:      pulumi.String(fmt.Sprintf("%s-simplelogin", config.GlobalName)),
+		ProjectId: pulumi.StringPtrFromPtr(scalewayConfig.Project),
+		Region:    pulumi.String(bucketRegion),
+		Tags:      pulumi.ToStringMap(config.CommonLabels()),
+	})
+	if bErr != nil {
+		return nil, bErr
+	}
+
+	bucketPolicy := pulumi.All(scwBucket.Name, app.Application.ID()).ApplyT(func(args []any) (string, error) {
+		bucketName, _ := args[0].(string)
+		applicationID, _ := args[1].(pulumi.ID)
+		return scalewayUtil.BucketPolicy(
+			"simplelogin",
+			bucketName,
+			fmt.Sprintf("application_id:%s", applicationID),
+			scalewayBucketActions,
+			simpleloginUtil.StorageOwnerPrincipals(simpleloginConfig),
+		)
+	}).(pulumi.StringOutput)
+	_, pErr := object.NewBucketPolicy(ctx, "scw-object-bucket-policy-simplelogin", &object.BucketPolicyArgs{
+		Bucket:    scwBucket.Name,
+		Policy:    bucketPolicy,
+		ProjectId: pulumi.StringPtrFromPtr(scalewayConfig.Project),
+		Region:    pulumi.String(bucketRegion),
+	})
+	if pErr != nil {
+		return nil, pErr
+	}
+
+	return pulumi.All(scwBucket.Name, app.Key.AccessKey, app.Key.SecretKey).ApplyT(func(args []any) map[string]any {
+		bucketName, _ := args[0].(string)
+		accessKeyID, _ := args[1].(string)
+		secretAccessKey, _ := args[2].(string)
+		return map[string]any{
+			"backend":         simpleloginUtil.StorageScaleway,
+			"bucket":          bucketName,
+			"region":          bucketRegion,
+			"endpoint":        simpleloginUtil.StorageEndpoint(simpleloginConfig, bucketRegion),
+			"accessKeyId":     accessKeyID,
+			"secretAccessKey": secretAccessKey,
+		}
+	}), nil
+}
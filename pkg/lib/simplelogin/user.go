@@ -21,9 +21,14 @@ func createAWSUser(
 	allow := "Allow"
 	policyDoc, _ := iam.GetPolicyDocument(ctx, &iam.GetPolicyDocumentArgs{
 		Statements: []iam.GetPolicyDocumentStatement{
+			{
+				Effect:    &allow,
+				Actions:   []string{"s3:ListBucket"},
+				Resources: []string{bucketArn},
+			},
 			{
 				Effect:  &allow,
-				Actions: []string{"s3:*"},
+				Actions: []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject"},
 				Resources: []string{
 					fmt.Sprintf("%s/*", bucketArn),
 				},
@@ -2,6 +2,7 @@ package traefik
 
 import (
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/install"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
@@ -14,14 +15,16 @@ import (
 // sshIPv4: The IPv4 address of the server to connect to via SSH.
 // privateKeyPem: The private key in PEM format to use for SSH authentication.
 // dnsConfig: DNS configuration.
+// serverConfig: Server configuration.
 // dependsOn: Pulumi resource option to specify dependencies.
 func Install(
 	ctx *pulumi.Context,
 	sshIPv4 pulumi.StringOutput,
 	privateKeyPem pulumi.StringOutput,
 	dnsConfig *dns.Config,
+	serverConfig *server.Config,
 	dependsOn pulumi.ResourceOrInvokeOption,
-) (*remote.Command, error) {
+) (*remote.Command, pulumi.MapOutput, error) {
 	conn := &remote.ConnectionArgs{
 		Host:       sshIPv4,
 		PrivateKey: privateKeyPem,
@@ -32,31 +35,42 @@ func Install(
 
 	opts, prepErr := install.Prepare(ctx, "traefik", conn, opts...)
 	if prepErr != nil {
-		return nil, prepErr
+		return nil, pulumi.MapOutput{}, prepErr
 	}
 
 	dockerCompose, dcErr := template.Render("./assets/traefik/docker-compose.yml.j2", map[string]any{
 		"gcpProject": dnsConfig.Project,
 	})
 	if dcErr != nil {
-		return nil, dcErr
+		return nil, pulumi.MapOutput{}, dcErr
 	}
 	dockerComposeCopy, dockerComposeHash, dcErr := install.DockerCompose(
 		ctx,
 		"traefik",
 		pulumi.String(dockerCompose),
 		false,
+		serverConfig,
 		conn,
 		opts...)
 	if dcErr != nil {
-		return nil, dcErr
+		return nil, pulumi.MapOutput{}, dcErr
+	}
+
+	opts, verifyImagesHash, viErr := install.VerifyImages(
+		ctx,
+		"traefik",
+		serverConfig,
+		conn,
+		opts...)
+	if viErr != nil {
+		return nil, pulumi.MapOutput{}, viErr
 	}
 
 	traefikYaml, dcErr := template.Render("./assets/traefik/traefik.yml.j2", map[string]any{
 		"acmeEmail": dnsConfig.Email,
 	})
 	if dcErr != nil {
-		return nil, dcErr
+		return nil, pulumi.MapOutput{}, dcErr
 	}
 	traefikYmlHash := file.WritePulumi("./outputs/traefik_traefik.yml", pulumi.String(traefikYaml)).
 		ApplyT(func(_ string) string {
@@ -75,17 +89,22 @@ func Install(
 
 	opts, systemdServiceHash, shErr := install.SystemDService(ctx, "traefik", conn, opts...)
 	if shErr != nil {
-		return nil, shErr
+		return nil, pulumi.MapOutput{}, shErr
 	}
 
 	installFn, iErr := file.ReadContents("./assets/traefik/install.sh")
 	if iErr != nil {
-		return nil, iErr
+		return nil, pulumi.MapOutput{}, iErr
 	}
-	return remote.NewCommand(ctx, "remote-command-install-traefik", &remote.CommandArgs{
+	cmd, cmdErr := remote.NewCommand(ctx, "remote-command-install-traefik", &remote.CommandArgs{
 		Create:     pulumi.StringPtr(installFn),
 		Update:     pulumi.StringPtr(installFn),
-		Triggers:   pulumi.Array{dockerComposeHash, pulumi.String(*systemdServiceHash), traefikYmlHash},
+		Triggers:   pulumi.Array{dockerComposeHash, pulumi.String(*systemdServiceHash), traefikYmlHash, verifyImagesHash},
 		Connection: conn,
 	}, append(opts, install.CollectResourceOptions([]pulumi.Output{pulumi.ToOutput(dockerComposeCopy), traefikYmlCopy})...)...)
+	if cmdErr != nil {
+		return nil, pulumi.MapOutput{}, cmdErr
+	}
+
+	return cmd, install.Images("./outputs/traefik_docker-compose.yml", dockerComposeHash), nil
 }
@@ -0,0 +1,13 @@
+package mail
+
+import "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+
+// BackupMXConfig defines configuration data for the secondary (backup) MX relay server.
+type BackupMXConfig struct {
+	// Enabled indicates if the backup MX relay server is deployed.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Server is the server configuration of the backup MX relay server.
+	Server *server.Config `yaml:"server,omitempty"`
+	// Priority is the MX priority of the backup MX relay server.
+	Priority *int `yaml:"priority,omitempty"`
+}
@@ -1,6 +1,9 @@
 package mail
 
-import "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
+import (
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/dns"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mailcow"
+)
 
 // Config defines configuration data for the mail server.
 type Config struct {
@@ -10,4 +13,20 @@ type Config struct {
 	Additional []*dns.DomainConfig `yaml:"additional,omitempty"`
 	// DkimSignHeaders is a list of headers to be signed with DKIM.
 	DkimSignHeaders []string `yaml:"dkimSignHeaders,omitempty"`
+	// BackupMX defines the secondary (backup) MX relay server.
+	BackupMX *BackupMXConfig `yaml:"backupMx,omitempty"`
+	// Smarthosts is a list of outbound SMTP smarthosts.
+	Smarthosts []*SmarthostConfig `yaml:"smarthosts,omitempty"`
+	// SPF defines the generated SPF records.
+	SPF *SPFConfig `yaml:"spf,omitempty"`
+	// Postfix defines additional Postfix configuration.
+	Postfix *PostfixConfig `yaml:"postfix,omitempty"`
+	// Rspamd defines the rspamd overrides.
+	Rspamd *RspamdConfig `yaml:"rspamd,omitempty"`
+	// Retention is a list of Dovecot retention policies (defaults to expunging 'Trash' and 'Junk' after 4 weeks).
+	Retention []*RetentionPolicy `yaml:"retention,omitempty"`
+	// Mailcow defines the objects managed in mailcow.
+	Mailcow *mailcow.Config `yaml:"mailcow,omitempty"`
+	// Probe defines the end-to-end mail flow probe.
+	Probe *ProbeConfig `yaml:"probe,omitempty"`
 }
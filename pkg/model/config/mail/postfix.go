@@ -0,0 +1,27 @@
+package mail
+
+// PostfixConfig defines additional Postfix configuration data of the mail server.
+type PostfixConfig struct {
+	// MessageSizeLimit is the maximal size of a message in bytes.
+	MessageSizeLimit *int `yaml:"messageSizeLimit,omitempty"`
+	// MyNetworks is a list of additional trusted networks (CIDR notation).
+	MyNetworks []string `yaml:"myNetworks,omitempty"`
+	// Parameters is a map of additional Postfix parameters (only 'smtpd_tls_*' and 'postscreen_*' are allowed).
+	Parameters map[string]string `yaml:"parameters,omitempty"`
+	// BodyChecks is a list of body check rules (defaults to the SimpleLogin client IP header rule).
+	BodyChecks []*CheckRule `yaml:"bodyChecks,omitempty"`
+	// ClientChecks is a list of client restriction rules (defaults to the SimpleLogin client IP header rule).
+	ClientChecks []*CheckRule `yaml:"clientChecks,omitempty"`
+}
+
+// CheckRule defines a single rule of a Postfix PCRE lookup table.
+type CheckRule struct {
+	// Pattern is the PCRE pattern including delimiters and flags (e.g. '/^Subject: test/i').
+	Pattern *string `yaml:"pattern,omitempty"`
+	// Action is the action to take when the pattern matches (e.g. 'IGNORE', 'REJECT').
+	Action *string `yaml:"action,omitempty"`
+	// Comment is an optional comment describing the rule.
+	Comment *string `yaml:"comment,omitempty"`
+	// Environments is a list of environments the rule applies to; an empty list applies it to all environments.
+	Environments []string `yaml:"environments,omitempty"`
+}
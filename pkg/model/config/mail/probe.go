@@ -0,0 +1,23 @@
+package mail
+
+// ProbeConfig defines the end-to-end mail flow probe run after deployments changing it.
+type ProbeConfig struct {
+	// Enabled indicates if the probe runs.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Mailbox is the address of the probe mailbox (defaults to 'probe@<main domain>').
+	Mailbox *string `yaml:"mailbox,omitempty"`
+	// Alias is a SimpleLogin alias forwarding to the probe mailbox (optional).
+	Alias *string `yaml:"alias,omitempty"`
+	// Run identifies the probe run; changing it (e.g. to the current date) runs the probe again (optional).
+	Run *string `yaml:"run,omitempty"`
+	// Ntfy defines the Ntfy notification of the probe outcome.
+	Ntfy *ProbeNtfyConfig `yaml:"ntfy,omitempty"`
+}
+
+// ProbeNtfyConfig defines the Ntfy notification of the probe outcome.
+type ProbeNtfyConfig struct {
+	// Enabled indicates if the outcome is pushed to Ntfy.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Topic is the Ntfy topic (defaults to 'mail-probe').
+	Topic *string `yaml:"topic,omitempty"`
+}
@@ -0,0 +1,15 @@
+package mail
+
+// RetentionPolicy defines a Dovecot retention policy expunging old messages of matching mailboxes.
+type RetentionPolicy struct {
+	// Name is the unique name of the policy (used as job name).
+	Name *string `yaml:"name,omitempty"`
+	// Mailbox is the mailbox name pattern (e.g. 'Trash', 'Archive/*').
+	Mailbox *string `yaml:"mailbox,omitempty"`
+	// Age is the Dovecot time interval after which messages are expunged (e.g. '4w', '30d').
+	Age *string `yaml:"age,omitempty"`
+	// Schedule is the ofelia cron schedule including seconds (e.g. '0 0 3 * * *') or a descriptor (e.g. '@daily').
+	Schedule *string `yaml:"schedule,omitempty"`
+	// Domains is a list of domains the policy applies to; an empty list applies it to all users.
+	Domains []string `yaml:"domains,omitempty"`
+}
@@ -0,0 +1,77 @@
+package mail
+
+// RspamdConfig defines the rspamd overrides of the mail server.
+type RspamdConfig struct {
+	// Actions defines the score thresholds of the rspamd actions.
+	Actions *RspamdActionsConfig `yaml:"actions,omitempty"`
+	// Multimap is a list of allow/deny lists.
+	Multimap []*RspamdMultimapConfig `yaml:"multimap,omitempty"`
+	// Greylisting defines the greylisting settings.
+	Greylisting *RspamdGreylistingConfig `yaml:"greylisting,omitempty"`
+	// RateLimits is a list of rate limits.
+	RateLimits []*RspamdRateLimitConfig `yaml:"rateLimits,omitempty"`
+	// ARC defines the ARC signing settings.
+	ARC *RspamdARCConfig `yaml:"arc,omitempty"`
+}
+
+// RspamdActionsConfig defines the score thresholds of the rspamd actions.
+type RspamdActionsConfig struct {
+	// Reject is the score to reject a message.
+	Reject *float64 `yaml:"reject,omitempty"`
+	// AddHeader is the score to add a spam header to a message.
+	AddHeader *float64 `yaml:"addHeader,omitempty"`
+	// RewriteSubject is the score to rewrite the subject of a message.
+	RewriteSubject *float64 `yaml:"rewriteSubject,omitempty"`
+	// Greylist is the score to greylist a message.
+	Greylist *float64 `yaml:"greylist,omitempty"`
+}
+
+// RspamdMultimapConfig defines an rspamd allow/deny list.
+type RspamdMultimapConfig struct {
+	// Name is the name of the list (used as symbol name).
+	Name *string `yaml:"name,omitempty"`
+	// Type is the type of the list ('from', 'rcpt', 'ip', 'header', 'selector', ...).
+	Type *string `yaml:"type,omitempty"`
+	// Header is the header to match for lists of type 'header'.
+	Header *string `yaml:"header,omitempty"`
+	// Filter is the filter to apply to the matched value (e.g. 'email:domain').
+	Filter *string `yaml:"filter,omitempty"`
+	// Action is the action to force on a match ('accept', 'reject', ...); mutually exclusive with score.
+	Action *string `yaml:"action,omitempty"`
+	// Score is the score to add on a match; mutually exclusive with action.
+	Score *float64 `yaml:"score,omitempty"`
+	// Description is the description of the list.
+	Description *string `yaml:"description,omitempty"`
+	// Entries is the list of entries.
+	Entries []string `yaml:"entries,omitempty"`
+}
+
+// RspamdGreylistingConfig defines the rspamd greylisting settings.
+type RspamdGreylistingConfig struct {
+	// Enabled indicates if greylisting is enabled.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Timeout is the time a sender has to wait before a retry is accepted (e.g. '5min').
+	Timeout *string `yaml:"timeout,omitempty"`
+	// Expire is the time a greylisted sender is remembered (e.g. '1d').
+	Expire *string `yaml:"expire,omitempty"`
+}
+
+// RspamdRateLimitConfig defines an rspamd rate limit.
+type RspamdRateLimitConfig struct {
+	// Name is the name of the rate limit.
+	Name *string `yaml:"name,omitempty"`
+	// Selector is the selector to limit by (e.g. 'user.lower', 'ip', 'rcpt.lower').
+	Selector *string `yaml:"selector,omitempty"`
+	// Burst is the burst size of the bucket.
+	Burst *int `yaml:"burst,omitempty"`
+	// Rate is the leak rate of the bucket (e.g. '10 / 1min').
+	Rate *string `yaml:"rate,omitempty"`
+}
+
+// RspamdARCConfig defines the rspamd ARC signing settings.
+type RspamdARCConfig struct {
+	// Enabled indicates if forwarded mail is signed and sealed with ARC.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Selector is the DNS selector of the ARC keys.
+	Selector *string `yaml:"selector,omitempty"`
+}
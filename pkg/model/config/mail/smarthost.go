@@ -0,0 +1,25 @@
+package mail
+
+// SmarthostConfig defines configuration data for an outbound SMTP smarthost (sender-dependent transport).
+type SmarthostConfig struct {
+	// Host is the hostname of the smarthost.
+	Host *string `yaml:"host,omitempty"`
+	// Port is the submission port of the smarthost.
+	Port *int `yaml:"port,omitempty"`
+	// Username is the SASL username to authenticate against the smarthost.
+	Username *string `yaml:"username,omitempty"`
+	// PasswordSecret is the secret stack configuration key holding the SASL password.
+	PasswordSecret *string `yaml:"passwordSecret,omitempty"`
+	// SPFInclude is the SPF include mechanism domain of the smarthost.
+	SPFInclude *string `yaml:"spfInclude,omitempty"`
+	// Domains is a list of domains relaying through the smarthost; an empty list applies it to all domains.
+	Domains []string `yaml:"domains,omitempty"`
+}
+
+// SPFConfig defines configuration data for the generated SPF records.
+type SPFConfig struct {
+	// Enabled indicates if SPF records are managed for all mail domains.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Policy is the qualifier of the final 'all' mechanism (e.g. '~all', '-all').
+	Policy *string `yaml:"policy,omitempty"`
+}
@@ -0,0 +1,17 @@
+package mailcow
+
+// Config defines configuration data for the objects managed in mailcow.
+type Config struct {
+	// Version is the mailcow release to run (e.g. '2026-07').
+	Version *string `yaml:"version,omitempty"`
+	// Settings defines the switches of the mailcow configuration.
+	Settings *Settings `yaml:"settings,omitempty"`
+	// Domains is a list of domain settings; the main and additional mail domains are always managed.
+	Domains []*DomainConfig `yaml:"domains,omitempty"`
+	// Mailboxes is a list of mailboxes.
+	Mailboxes []*MailboxConfig `yaml:"mailboxes,omitempty"`
+	// Aliases is a list of aliases.
+	Aliases []*AliasConfig `yaml:"aliases,omitempty"`
+	// DomainAliases is a list of alias domains.
+	DomainAliases []*DomainAliasConfig `yaml:"domainAliases,omitempty"`
+}
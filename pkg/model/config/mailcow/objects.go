@@ -0,0 +1,61 @@
+package mailcow
+
+// DomainConfig defines the settings of a mail domain in mailcow.
+type DomainConfig struct {
+	// Name is the domain name (must be the main or an additional mail domain).
+	Name *string `yaml:"name,omitempty"`
+	// Description is the description of the domain.
+	Description *string `yaml:"description,omitempty"`
+	// Mailboxes is the maximal number of mailboxes.
+	Mailboxes *int `yaml:"mailboxes,omitempty"`
+	// Aliases is the maximal number of aliases.
+	Aliases *int `yaml:"aliases,omitempty"`
+	// Quota is the total quota of the domain in MiB.
+	Quota *int `yaml:"quota,omitempty"`
+	// DefaultMailboxQuota is the default quota of a mailbox in MiB.
+	DefaultMailboxQuota *int `yaml:"defaultMailboxQuota,omitempty"`
+	// MaxMailboxQuota is the maximal quota of a mailbox in MiB.
+	MaxMailboxQuota *int `yaml:"maxMailboxQuota,omitempty"`
+	// RateLimit is the outbound rate limit of the domain.
+	RateLimit *RateLimitConfig `yaml:"rateLimit,omitempty"`
+}
+
+// RateLimitConfig defines an outbound rate limit.
+type RateLimitConfig struct {
+	// Value is the number of messages per frame.
+	Value *int `yaml:"value,omitempty"`
+	// Frame is the time frame of the rate limit ('s', 'm', 'h', 'd').
+	Frame *string `yaml:"frame,omitempty"`
+}
+
+// MailboxConfig defines a mailbox in mailcow.
+type MailboxConfig struct {
+	// Address is the address of the mailbox.
+	Address *string `yaml:"address,omitempty"`
+	// Name is the full name of the mailbox owner.
+	Name *string `yaml:"name,omitempty"`
+	// Quota is the quota of the mailbox in MiB.
+	Quota *int `yaml:"quota,omitempty"`
+	// Active indicates if the mailbox is active.
+	Active *bool `yaml:"active,omitempty"`
+}
+
+// AliasConfig defines an alias in mailcow.
+type AliasConfig struct {
+	// Address is the address of the alias.
+	Address *string `yaml:"address,omitempty"`
+	// Goto is a list of destination addresses.
+	Goto []string `yaml:"goto,omitempty"`
+	// Active indicates if the alias is active.
+	Active *bool `yaml:"active,omitempty"`
+}
+
+// DomainAliasConfig defines an alias domain in mailcow.
+type DomainAliasConfig struct {
+	// Alias is the name of the alias domain.
+	Alias *string `yaml:"alias,omitempty"`
+	// Target is the name of the target domain.
+	Target *string `yaml:"target,omitempty"`
+	// Active indicates if the alias domain is active.
+	Active *bool `yaml:"active,omitempty"`
+}
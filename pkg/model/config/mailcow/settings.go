@@ -0,0 +1,65 @@
+package mailcow
+
+// Settings defines the switches of the mailcow configuration (mailcow.conf).
+type Settings struct {
+	// Preset is the resource preset ('small', 'medium', 'large'); defaults to the preset of the server type.
+	Preset *string `yaml:"preset,omitempty"`
+	// SkipClamd indicates if ClamAV is disabled.
+	SkipClamd *bool `yaml:"skipClamd,omitempty"`
+	// SkipFTS indicates if the full-text search is disabled.
+	SkipFTS *bool `yaml:"skipFts,omitempty"`
+	// FTSHeap is the maximal heap size of the full-text search indexer in MB.
+	FTSHeap *int `yaml:"ftsHeap,omitempty"`
+	// FTSProcs is the maximal number of full-text search indexer processes.
+	FTSProcs *int `yaml:"ftsProcs,omitempty"`
+	// SOGo defines the SOGo switches.
+	SOGo *SOGoSettings `yaml:"sogo,omitempty"`
+	// MaildirGCTime is the time in minutes deleted mailboxes are kept before they are removed.
+	MaildirGCTime *int `yaml:"maildirGcTime,omitempty"`
+	// Timezone is the timezone of the containers (e.g. 'Europe/Vienna').
+	Timezone *string `yaml:"timezone,omitempty"`
+	// AdditionalSAN is a list of additional certificate names (e.g. 'imap.*').
+	AdditionalSAN []string `yaml:"additionalSan,omitempty"`
+	// Watchdog defines the watchdog switches.
+	Watchdog *WatchdogSettings `yaml:"watchdog,omitempty"`
+	// PasswordScheme is the password hashing scheme (e.g. 'BLF-CRYPT').
+	PasswordScheme *string `yaml:"passwordScheme,omitempty"`
+}
+
+// SOGoSettings defines the SOGo switches.
+type SOGoSettings struct {
+	// Skip indicates if SOGo (webmail, DAV, and ActiveSync) is disabled.
+	Skip *bool `yaml:"skip,omitempty"`
+	// ExpireSession is the session timeout in minutes.
+	ExpireSession *int `yaml:"expireSession,omitempty"`
+	// AllowAdminEmailLogin indicates if admins can log into SOGo as mail users.
+	AllowAdminEmailLogin *bool `yaml:"allowAdminEmailLogin,omitempty"`
+}
+
+// WatchdogSettings defines the watchdog switches.
+type WatchdogSettings struct {
+	// Enabled indicates if the watchdog restarts unhealthy containers.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// NotifyBan indicates if banned IP addresses are notified.
+	NotifyBan *bool `yaml:"notifyBan,omitempty"`
+	// NotifyStart indicates if a notification is sent when the watchdog starts.
+	NotifyStart *bool `yaml:"notifyStart,omitempty"`
+	// ExternalChecks indicates if the external open relay checks are enabled.
+	ExternalChecks *bool `yaml:"externalChecks,omitempty"`
+	// Verbose indicates if the watchdog logs verbosely.
+	Verbose *bool `yaml:"verbose,omitempty"`
+	// NotifyEmail is a list of external e-mail addresses notified by the watchdog.
+	NotifyEmail []string `yaml:"notifyEmail,omitempty"`
+	// Subject is the subject of the watchdog notifications.
+	Subject *string `yaml:"subject,omitempty"`
+	// Ntfy defines the watchdog notifications to the Ntfy server.
+	Ntfy *WatchdogNtfySettings `yaml:"ntfy,omitempty"`
+}
+
+// WatchdogNtfySettings defines the watchdog notifications to the Ntfy server.
+type WatchdogNtfySettings struct {
+	// Enabled indicates if the watchdog notifies the Ntfy topic.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Topic is the Ntfy topic to notify.
+	Topic *string `yaml:"topic,omitempty"`
+}
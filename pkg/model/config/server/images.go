@@ -0,0 +1,23 @@
+package server
+
+// ImagesConfig defines how the container images of the docker compose files are pinned and verified.
+type ImagesConfig struct {
+	// PinDigests pins every image to a digest, resolving the digests missing in the templates at deployment.
+	PinDigests *bool `yaml:"pinDigests,omitempty"`
+	// Verify are the cosign policies the pulled images are verified against before they are started.
+	Verify []ImageVerifyConfig `yaml:"verify,omitempty"`
+}
+
+// ImageVerifyConfig defines how the signatures or attestations of matching images are verified with cosign.
+type ImageVerifyConfig struct {
+	// Repository is the pattern matched against '<registry>/<repository>' (e.g. 'ghcr.io/mailcow/*').
+	Repository *string `yaml:"repository,omitempty"`
+	// Key is the PEM encoded public key the images are signed with (optional).
+	Key *string `yaml:"key,omitempty"`
+	// Identity is a regular expression matching the certificate identity of keyless signatures (optional).
+	Identity *string `yaml:"identity,omitempty"`
+	// Issuer is the OIDC issuer of keyless signatures (optional).
+	Issuer *string `yaml:"issuer,omitempty"`
+	// Attestation is the predicate type of the attestation to verify instead of the signature (optional, e.g. 'slsaprovenance').
+	Attestation *string `yaml:"attestation,omitempty"`
+}
@@ -10,4 +10,10 @@ type Config struct {
 	IPv4 *string `yaml:"ipv4,omitempty"`
 	// PublicSSH indicates if public SSH access is enabled.
 	PublicSSH *bool `yaml:"publicSsh,omitempty"`
+	// SnapshotRetention is the number of pre-change snapshots kept per component.
+	SnapshotRetention *int `yaml:"snapshotRetention,omitempty"`
+	// Images defines how the container images are pinned and verified.
+	Images *ImagesConfig `yaml:"images,omitempty"`
+	// RegistryMirror defines the pull-through cache of Docker Hub on the server.
+	RegistryMirror *RegistryMirrorConfig `yaml:"registryMirror,omitempty"`
 }
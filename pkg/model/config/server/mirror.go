@@ -0,0 +1,18 @@
+package server
+
+// RegistryMirrorConfig defines the pull-through cache of Docker Hub on the server.
+// Images of other registries (e.g. mailcow's images on GHCR) are pulled from their registries directly.
+type RegistryMirrorConfig struct {
+	// Enabled indicates if the registry mirror is deployed and used by Docker.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Region is the Scaleway region of the bucket storing the cached images.
+	Region *string `yaml:"region,omitempty"`
+	// TTL is the duration cached images are kept after their last use (e.g. '168h').
+	TTL *string `yaml:"ttl,omitempty"`
+	// Username is the Docker Hub user to pull with a higher rate limit (optional).
+	Username *string `yaml:"username,omitempty"`
+	// PasswordSecret is the secret stack configuration key holding the Docker Hub password or access token (optional).
+	PasswordSecret *string `yaml:"passwordSecret,omitempty"`
+	// OwnerPrincipals are the Scaleway principals keeping full access to the bucket.
+	OwnerPrincipals []string `yaml:"ownerPrincipals,omitempty"`
+}
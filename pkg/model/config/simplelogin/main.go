@@ -8,6 +8,44 @@ type Config struct {
 	Mail *MailConfig `yaml:"mail,omitempty"`
 	// OIDC defines the OIDC configuration for SimpleLogin.
 	OIDC *OIDCConfig `yaml:"oidc,omitempty"`
+	// Settings defines the application settings of SimpleLogin.
+	Settings *Settings `yaml:"settings,omitempty"`
+	// Storage defines the object storage of the SimpleLogin uploads.
+	Storage *StorageConfig `yaml:"storage,omitempty"`
+	// Web defines the sizing of the web interface.
+	Web *WebConfig `yaml:"web,omitempty"`
+	// Redis defines the Redis configuration.
+	Redis *RedisConfig `yaml:"redis,omitempty"`
+	// Backup defines the backup of the SimpleLogin database.
+	Backup *BackupConfig `yaml:"backup,omitempty"`
+}
+
+// WebConfig defines the sizing of the SimpleLogin web interface (gunicorn).
+type WebConfig struct {
+	// Workers is the number of worker processes; defaults to the preset of the server type.
+	Workers *int `yaml:"workers,omitempty"`
+	// Threads is the number of threads per worker; defaults to the preset of the server type.
+	Threads *int `yaml:"threads,omitempty"`
+	// Timeout is the request timeout in seconds.
+	Timeout *int `yaml:"timeout,omitempty"`
+}
+
+// RedisConfig defines the Redis configuration of SimpleLogin.
+type RedisConfig struct {
+	// Persistence indicates if the Redis data is persisted (append-only file) and backed up.
+	Persistence *bool `yaml:"persistence,omitempty"`
+}
+
+// StorageConfig defines the object storage of the SimpleLogin uploads.
+type StorageConfig struct {
+	// Backend is the storage backend ('aws', 'scaleway', or 'local').
+	Backend *string `yaml:"backend,omitempty"`
+	// Region is the region of the bucket.
+	Region *string `yaml:"region,omitempty"`
+	// Endpoint is the endpoint of an S3-compatible provider.
+	Endpoint *string `yaml:"endpoint,omitempty"`
+	// OwnerPrincipals is a list of Scaleway principals (e.g. 'user_id:<id>') keeping full access to the bucket.
+	OwnerPrincipals []string `yaml:"ownerPrincipals,omitempty"`
 }
 
 // MailConfig defines mail-related configuration for SimpleLogin.
@@ -20,6 +58,20 @@ type MailConfig struct {
 	ZoneID *string `yaml:"zoneId,omitempty"`
 	// Project defines the project configuration.
 	Project *string `yaml:"project,omitempty"`
+	// AliasDomains defines additional domains to create aliases in.
+	AliasDomains []*AliasDomainConfig `yaml:"aliasDomains,omitempty"`
+}
+
+// AliasDomainConfig defines an additional SimpleLogin alias domain.
+type AliasDomainConfig struct {
+	// Domain defines the alias domain.
+	Domain *string `yaml:"domain,omitempty"`
+	// ZoneID defines the zone ID configuration.
+	ZoneID *string `yaml:"zoneId,omitempty"`
+	// Project defines the project configuration.
+	Project *string `yaml:"project,omitempty"`
+	// Premium defines whether the domain is only available to premium users.
+	Premium *bool `yaml:"premium,omitempty"`
 }
 
 // OIDCConfig defines OIDC-related configuration for SimpleLogin.
@@ -31,3 +83,19 @@ type OIDCConfig struct {
 	// ClientSecret defines the client secret configuration.
 	ClientSecret *string `yaml:"clientSecret,omitempty"`
 }
+
+// BackupConfig defines the backup of the SimpleLogin database.
+type BackupConfig struct {
+	// RetentionDays is the number of days backups are kept.
+	RetentionDays *int `yaml:"retentionDays,omitempty"`
+	// PITR defines the point-in-time recovery of the database.
+	PITR *PITRConfig `yaml:"pitr,omitempty"`
+}
+
+// PITRConfig defines the point-in-time recovery of the SimpleLogin database.
+type PITRConfig struct {
+	// Enabled indicates if the WAL is archived and base backups are taken.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// ArchiveTimeout is the time in seconds after which a WAL segment is archived even if it isn't full.
+	ArchiveTimeout *int `yaml:"archiveTimeout,omitempty"`
+}
@@ -0,0 +1,51 @@
+package simplelogin
+
+// Settings defines the application settings of SimpleLogin (env).
+type Settings struct {
+	// DisableRegistration indicates if new users can't register.
+	DisableRegistration *bool `yaml:"disableRegistration,omitempty"`
+	// DisableOnboarding indicates if the onboarding e-mails are disabled.
+	DisableOnboarding *bool `yaml:"disableOnboarding,omitempty"`
+	// PremiumByDefault indicates if all users are marked as lifetime premium users on start.
+	PremiumByDefault *bool `yaml:"premiumByDefault,omitempty"`
+	// AllowedRedirectDomains is a list of domains the web interface may redirect to.
+	AllowedRedirectDomains []string `yaml:"allowedRedirectDomains,omitempty"`
+	// Nameservers is a list of nameservers used for the DNS checks (e.g. custom domain verification).
+	Nameservers []string `yaml:"nameservers,omitempty"`
+	// EnforceSPF indicates if the SPF of the senders of reverse aliases is enforced.
+	EnforceSPF *bool `yaml:"enforceSpf,omitempty"`
+	// Support defines the support contact.
+	Support *SupportSettings `yaml:"support,omitempty"`
+	// OIDC defines the OIDC login settings.
+	OIDC *OIDCSettings `yaml:"oidc,omitempty"`
+	// Features defines the feature flags.
+	Features *FeatureSettings `yaml:"features,omitempty"`
+}
+
+// SupportSettings defines the support contact of SimpleLogin.
+type SupportSettings struct {
+	// Name is the sender name of the support e-mails.
+	Name *string `yaml:"name,omitempty"`
+	// Email is the address of the support e-mails.
+	Email *string `yaml:"email,omitempty"`
+}
+
+// OIDCSettings defines the OIDC login settings of SimpleLogin.
+type OIDCSettings struct {
+	// Scopes is the list of requested scopes.
+	Scopes []string `yaml:"scopes,omitempty"`
+	// NameField is the claim holding the name of the user.
+	NameField *string `yaml:"nameField,omitempty"`
+	// Icon is the Font Awesome icon of the login button.
+	Icon *string `yaml:"icon,omitempty"`
+}
+
+// FeatureSettings defines the feature flags of SimpleLogin.
+type FeatureSettings struct {
+	// AliasSuffix indicates if random suffixes are appended to custom aliases.
+	AliasSuffix *bool `yaml:"aliasSuffix,omitempty"`
+	// PGP indicates if the mailboxes may encrypt forwarded e-mails with their PGP keys.
+	PGP *bool `yaml:"pgp,omitempty"`
+	// HIBPAPIKeys is a list of Have I Been Pwned API keys enabling the breach checks of aliases.
+	HIBPAPIKeys []string `yaml:"hibpApiKeys,omitempty"`
+}
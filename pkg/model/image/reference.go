@@ -0,0 +1,13 @@
+package image
+
+// Reference represents a parsed container image reference.
+type Reference struct {
+	// Registry is the registry hosting the image (e.g. 'docker.io').
+	Registry string
+	// Repository is the repository of the image within the registry (e.g. 'library/postgres').
+	Repository string
+	// Tag is the tag of the image (optional).
+	Tag string
+	// Digest is the content digest the image is pinned to (optional).
+	Digest string
+}
@@ -0,0 +1,15 @@
+package ntfy
+
+import "github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+// User defines a provisioned Ntfy user with read-write access to a single topic.
+type User struct {
+	// Name is the name of the user.
+	Name string
+	// PasswordHash is the bcrypt hash of the user's password.
+	PasswordHash pulumi.StringOutput
+	// Topic is the topic the user has access to.
+	Topic string
+	// Token is the access token of the user.
+	Token pulumi.StringOutput
+}
@@ -6,6 +6,8 @@ import "github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 type Data struct {
 	// Resource is the Pulumi resource representing the server.
 	Resource pulumi.Resource
+	// ID is the identifier of the server.
+	ID pulumi.IntOutput
 	// Hostname is the hostname of the server.
 	Hostname pulumi.StringOutput
 	// PrivateIPv4 is the private IPv4 address of the server.
@@ -18,4 +20,10 @@ type Data struct {
 	SSHIPv4 pulumi.StringOutput
 	// Network is the network of the server.
 	Network pulumi.StringOutput
+	// NetworkID is the identifier of the network the server is attached to.
+	NetworkID *pulumi.IntOutput
+	// SSHKeyID is the identifier of the SSH key deployed to the server.
+	SSHKeyID pulumi.StringOutput
+	// FirewallID is the identifier of the firewall attached to the server.
+	FirewallID pulumi.IntOutput
 }
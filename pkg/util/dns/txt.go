@@ -0,0 +1,31 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitByLength splits a string into chunks and formats the result according to the DNS record type.
+// value: The string to be split.
+// typ: The DNS record type (e.g., "TXT").
+func SplitByLength(value string, typ string) string {
+	const maxLen = 200
+	if value == "" {
+		return ""
+	}
+
+	var parts []string
+	for i := 0; i < len(value); i += maxLen {
+		end := i + maxLen
+		if end > len(value) {
+			end = len(value)
+		}
+		parts = append(parts, value[i:end])
+	}
+
+	if len(parts) > 1 || typ == "TXT" {
+		return fmt.Sprintf("\"%s\"", strings.Join(parts, "\" \""))
+	}
+
+	return strings.Join(parts, "")
+}
@@ -2,6 +2,9 @@ package install
 
 import (
 	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
 
 	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/config"
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
@@ -11,46 +14,35 @@ import (
 )
 
 // Cron executes the cron job setup for the given software on the remote server.
+// Every script template in the cron directory (<name>-<script>.j2) is rendered and installed to /bin/<name>-<script>.
 // ctx: Pulumi context.
-// name: The name of the software (used to locate the cron job script).
+// name: The name of the software (used to locate the cron job scripts).
+// data: Additional data to render the scripts with (optional).
 // conn: The remote connection arguments.
 // opts: Additional Pulumi resource options.
 func Cron(
 	ctx *pulumi.Context,
 	name string,
+	data map[string]any,
 	conn *remote.ConnectionArgs,
 	opts ...pulumi.ResourceOption,
 ) ([]pulumi.Output, error) {
-	backupFile, dcErr := template.Render(
-		fmt.Sprintf("./assets/%s/cron/%s-backup.j2", name, name),
-		map[string]any{
-			"bucket": map[string]string{
-				"id":   config.BackupBucketID,
-				"path": config.BackupBucketPath,
-			},
-		},
-	)
-	if dcErr != nil {
-		return nil, dcErr
+	scripts, gErr := filepath.Glob(fmt.Sprintf("./assets/%s/cron/%s-*.j2", name, name))
+	if gErr != nil {
+		return nil, gErr
+	}
+
+	scriptCopies := []pulumi.Output{}
+	triggers := pulumi.Array{}
+	for _, script := range scripts {
+		scriptName := strings.TrimSuffix(filepath.Base(script), ".j2")
+		scriptHash, scriptCopy, sErr := cronScript(ctx, name, scriptName, data, conn, opts...)
+		if sErr != nil {
+			return nil, sErr
+		}
+		scriptCopies = append(scriptCopies, scriptCopy)
+		triggers = append(triggers, scriptHash)
 	}
-	backupFileHash := file.WritePulumi(fmt.Sprintf("./outputs/%s_backup", name), pulumi.String(backupFile)).
-		ApplyT(func(_ string) string {
-			hash, _ := file.Hash(fmt.Sprintf("./outputs/%s_backup", name))
-			return *hash
-		})
-	backupFileCopy := backupFileHash.ApplyT(func(_ string) pulumi.ResourceOption {
-		cmd, _ := remote.NewCopyToRemote(
-			ctx,
-			fmt.Sprintf("remote-copy-%s-backup", name),
-			&remote.CopyToRemoteArgs{
-				Source:     pulumi.NewFileAsset(fmt.Sprintf("./outputs/%s_backup", name)),
-				RemotePath: pulumi.Sprintf("/bin/%s-backup", name),
-				Triggers:   pulumi.Array{backupFileHash},
-				Connection: conn,
-			},
-			opts...)
-		return pulumi.DependsOn([]pulumi.Resource{cmd})
-	})
 
 	cronFileHash, shErr := file.Hash(fmt.Sprintf("./assets/%s/cron/cron", name))
 	if shErr != nil {
@@ -81,7 +73,7 @@ func Cron(
 		&remote.CommandArgs{
 			Create:     pulumi.StringPtr(cronInstallFn),
 			Update:     pulumi.StringPtr(cronInstallFn),
-			Triggers:   pulumi.Array{pulumi.String(*cronFileHash), backupFileHash},
+			Triggers:   append(pulumi.Array{pulumi.String(*cronFileHash)}, triggers...),
 			Connection: conn,
 		},
 		opts...)
@@ -89,8 +81,55 @@ func Cron(
 		return nil, ciErr
 	}
 
-	return []pulumi.Output{
-		backupFileCopy,
-		pulumi.ToOutput(pulumi.DependsOn([]pulumi.Resource{cronInstall})),
-	}, nil
+	return append(scriptCopies, pulumi.ToOutput(pulumi.DependsOn([]pulumi.Resource{cronInstall}))), nil
+}
+
+// cronScript renders a cron job script and copies it to /bin on the remote server.
+// ctx: Pulumi context.
+// name: The name of the software.
+// scriptName: The name of the script (<name>-<script>).
+// data: Additional data to render the script with (optional).
+// conn: The remote connection arguments.
+// opts: Additional Pulumi resource options.
+func cronScript(
+	ctx *pulumi.Context,
+	name string,
+	scriptName string,
+	data map[string]any,
+	conn *remote.ConnectionArgs,
+	opts ...pulumi.ResourceOption,
+) (pulumi.Output, pulumi.Output, error) {
+	values := map[string]any{
+		"bucket": map[string]string{
+			"id":   config.BackupBucketID,
+			"path": config.BackupBucketPath,
+		},
+	}
+	maps.Copy(values, data)
+
+	content, rErr := template.Render(fmt.Sprintf("./assets/%s/cron/%s.j2", name, scriptName), values)
+	if rErr != nil {
+		return nil, nil, rErr
+	}
+	// the output file keeps the naming of the backup script (<name>_backup)
+	outputFile := fmt.Sprintf("./outputs/%s_%s", name, strings.TrimPrefix(scriptName, name+"-"))
+	scriptHash := file.WritePulumi(outputFile, pulumi.String(content)).
+		ApplyT(func(_ string) string {
+			hash, _ := file.Hash(outputFile)
+			return *hash
+		})
+	scriptCopy := scriptHash.ApplyT(func(_ string) pulumi.ResourceOption {
+		cmd, _ := remote.NewCopyToRemote(
+			ctx,
+			fmt.Sprintf("remote-copy-%s", scriptName),
+			&remote.CopyToRemoteArgs{
+				Source:     pulumi.NewFileAsset(outputFile),
+				RemotePath: pulumi.Sprintf("/bin/%s", scriptName),
+				Triggers:   pulumi.Array{scriptHash},
+				Connection: conn,
+			},
+			opts...)
+		return pulumi.DependsOn([]pulumi.Resource{cmd})
+	})
+	return scriptHash, scriptCopy, nil
 }
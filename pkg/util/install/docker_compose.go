@@ -6,6 +6,8 @@ import (
 	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
 	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
 )
 
 // DockerCompose creates a docker-compose file for the given software on the remote server.
@@ -13,6 +15,7 @@ import (
 // name: The name of the software (used to locate the service file).
 // content: The docker-compose content to be written.
 // override: Whether this is an override file.
+// serverConfig: The server configuration (to pin the images).
 // conn: The remote connection arguments.
 // opts: Additional Pulumi resource options.
 func DockerCompose(
@@ -20,6 +23,7 @@ func DockerCompose(
 	name string,
 	content pulumi.StringInput,
 	override bool,
+	serverConfig *server.Config,
 	conn *remote.ConnectionArgs,
 	opts ...pulumi.ResourceOption,
 ) (pulumi.ResourceOption, *pulumi.StringOutput, error) {
@@ -28,7 +32,11 @@ func DockerCompose(
 		filename = "docker-compose.override.yml"
 	}
 
-	dockerComposeHash, _ := file.WritePulumi(fmt.Sprintf("./outputs/%s_%s", name, filename), content).
+	pinned, _ := content.ToStringOutput().ApplyT(func(c string) (string, error) {
+		return PinImages(c, serverConfig)
+	}).(pulumi.StringOutput)
+
+	dockerComposeHash, _ := file.WritePulumi(fmt.Sprintf("./outputs/%s_%s", name, filename), pinned).
 		ApplyT(func(_ string) string {
 			hash, _ := file.Hash(fmt.Sprintf("./outputs/%s_%s", name, filename))
 			return *hash
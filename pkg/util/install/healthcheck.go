@@ -0,0 +1,89 @@
+package install
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// healthcheckTimeout is the time in seconds the health checks have to pass.
+const healthcheckTimeout = 600
+
+// HealthcheckScript copies the health check script for the given software to the remote server.
+// ctx: Pulumi context.
+// name: The name of the software (used as the installation directory).
+// conn: The remote connection arguments.
+// opts: Additional Pulumi resource options.
+func HealthcheckScript(
+	ctx *pulumi.Context,
+	name string,
+	conn *remote.ConnectionArgs,
+	opts ...pulumi.ResourceOption,
+) ([]pulumi.ResourceOption, *string, error) {
+	healthcheckHash, hhErr := file.Hash("./assets/install/healthcheck.sh")
+	if hhErr != nil {
+		return nil, nil, hhErr
+	}
+	healthcheckCopy, hcErr := remote.NewCopyToRemote(
+		ctx,
+		fmt.Sprintf("remote-copy-%s-healthcheck", name),
+		&remote.CopyToRemoteArgs{
+			Source:     pulumi.NewFileAsset("./assets/install/healthcheck.sh"),
+			RemotePath: pulumi.Sprintf("/opt/%s/healthcheck.sh", name),
+			Triggers:   pulumi.Array{pulumi.String(*healthcheckHash)},
+			Connection: conn,
+		},
+		opts...)
+	if hcErr != nil {
+		return nil, nil, hcErr
+	}
+	opts = append(opts, pulumi.DependsOn([]pulumi.Resource{healthcheckCopy}))
+	return opts, healthcheckHash, nil
+}
+
+// HealthcheckCommand returns the command verifying the health of the given software.
+// It waits until the docker compose health checks pass, the URL answers with 200 through traefik,
+// and SMTP greets with the hostname (if given).
+// name: The name of the software (used as the installation directory).
+// url: The HTTPS URL served through traefik.
+// smtpHostname: The hostname SMTP has to greet with (optional, empty to skip the check).
+func HealthcheckCommand(name string, url string, smtpHostname string) string {
+	return fmt.Sprintf(
+		"HEALTHCHECK_TIMEOUT=%d sh /opt/%s/healthcheck.sh /opt/%s '%s' '%s'",
+		healthcheckTimeout,
+		name,
+		name,
+		url,
+		smtpHostname,
+	)
+}
+
+// Healthcheck verifies the health of the given software on the remote server.
+// The Pulumi update fails with the container logs if the checks don't pass in time.
+// ctx: Pulumi context.
+// name: The name of the software (used as the installation directory).
+// url: The HTTPS URL served through traefik.
+// smtpHostname: The hostname SMTP has to greet with (optional, empty to skip the check).
+// triggers: The triggers to rerun the health checks.
+// conn: The remote connection arguments.
+// opts: Additional Pulumi resource options.
+func Healthcheck(
+	ctx *pulumi.Context,
+	name string,
+	url string,
+	smtpHostname string,
+	triggers pulumi.Array,
+	conn *remote.ConnectionArgs,
+	opts ...pulumi.ResourceOption,
+) []pulumi.ResourceOption {
+	healthcheckFn := HealthcheckCommand(name, url, smtpHostname)
+	healthcheck, _ := remote.NewCommand(ctx, fmt.Sprintf("remote-command-healthcheck-%s", name), &remote.CommandArgs{
+		Create:     pulumi.String(healthcheckFn),
+		Update:     pulumi.String(healthcheckFn),
+		Triggers:   triggers,
+		Connection: conn,
+	}, opts...)
+	return append(opts, pulumi.DependsOn([]pulumi.Resource{healthcheck}))
+}
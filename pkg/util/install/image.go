@@ -0,0 +1,93 @@
+package install
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/stretchr/testify/assert/yaml"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/image"
+)
+
+// defaultRegistry is the registry used for image references without an explicit registry.
+const defaultRegistry = "docker.io"
+
+// defaultNamespace is the namespace of official images on the default registry.
+const defaultNamespace = "library"
+
+// ParseImage parses a container image reference (e.g. 'ghcr.io/org/app:1.0@sha256:...').
+// ref: The image reference to parse.
+func ParseImage(ref string) image.Reference {
+	parsed := image.Reference{Registry: defaultRegistry}
+
+	name, digest, _ := strings.Cut(ref, "@")
+	parsed.Digest = digest
+
+	// the tag follows the last colon, unless the colon belongs to a registry port (e.g. 'registry:5000/app')
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		parsed.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	// the first path component is a registry if it looks like a host
+	if first, rest, found := strings.Cut(name, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		parsed.Registry = first
+		name = rest
+	}
+
+	if parsed.Registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = defaultNamespace + "/" + name
+	}
+	parsed.Repository = name
+
+	return parsed
+}
+
+// readImages reads the image references of all services from a Docker Compose file.
+// file: Path to the Docker Compose YAML file.
+func readImages(file string) map[string]image.Reference {
+	data, rErr := os.ReadFile(file)
+	if rErr != nil {
+		return nil
+	}
+
+	var parsed struct {
+		Services map[string]struct {
+			Image string `yaml:"image"`
+		} `yaml:"services"`
+	}
+	if pErr := yaml.Unmarshal(data, &parsed); pErr != nil {
+		return nil
+	}
+
+	images := map[string]image.Reference{}
+	for service, definition := range parsed.Services {
+		if definition.Image == "" {
+			continue
+		}
+		images[service] = ParseImage(definition.Image)
+	}
+	return images
+}
+
+// Images reads the image inventory of all services from a Docker Compose file.
+// file: Path to the Docker Compose YAML file.
+// dockerComposeHash: Pulumi StringOutput representing the hash of the Docker Compose file.
+func Images(file string, dockerComposeHash pulumi.Output) pulumi.MapOutput {
+	images, _ := dockerComposeHash.ApplyT(func(_ any) map[string]any {
+		inventory := map[string]any{}
+		for service, ref := range readImages(file) {
+			inventory[service] = map[string]any{
+				"registry":   ref.Registry,
+				"repository": ref.Repository,
+				"tag":        ref.Tag,
+				"digest":     ref.Digest,
+			}
+		}
+		return inventory
+	}).(pulumi.MapOutput)
+
+	return images
+}
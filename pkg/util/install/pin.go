@@ -0,0 +1,171 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/file"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/template"
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/lib/registry"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/shell"
+)
+
+// cosignImage is the image of cosign verifying the signatures and attestations (the tag is bumped by renovate).
+const cosignImage = "ghcr.io/sigstore/cosign/cosign:v2.6.1"
+
+// imageLineRegexp matches the image lines of a docker compose file.
+var imageLineRegexp = regexp.MustCompile(`(?m)^(\s*image:\s*)["']?([^"'\s]+)["']?[ \t]*$`)
+
+// PinImages pins every image of a docker compose file to a digest if pinning is enabled.
+// Digests written into the templates are kept; the digests of the other images are resolved from their registries.
+// The content is returned unchanged if pinning is disabled.
+// content: The docker compose content.
+// serverConfig: The server configuration.
+func PinImages(content string, serverConfig *server.Config) (string, error) {
+	if !serverUtil.PinDigests(serverConfig) {
+		return content, nil
+	}
+
+	images := map[string]string{}
+	var pinErr error
+	pinned := imageLineRegexp.ReplaceAllStringFunc(content, func(line string) string {
+		match := imageLineRegexp.FindStringSubmatch(line)
+		ref := match[2]
+		if _, ok := images[ref]; !ok {
+			image, iErr := pinImage(ref)
+			if iErr != nil {
+				pinErr = iErr
+				return line
+			}
+			images[ref] = image
+		}
+		return match[1] + images[ref]
+	})
+	if pinErr != nil {
+		return "", pinErr
+	}
+	return pinned, nil
+}
+
+// pinImage pins an image reference to its digest; a digest of the reference is kept.
+// ref: The image reference.
+func pinImage(ref string) (string, error) {
+	digest, dErr := registry.Digest(context.Background(), ParseImage(ref))
+	if dErr != nil {
+		return "", dErr
+	}
+	name, _, _ := strings.Cut(ref, "@")
+	return fmt.Sprintf("%s@%s", name, digest), nil
+}
+
+// VerifyImages copies the script verifying the images of a docker compose project with cosign to the remote server.
+// The script pulls the images of all compose files of the project and verifies the pulled digests of every image
+// matching a policy of 'server.images.verify', failing on the first mismatch;
+// it has nothing to verify if no policy is configured.
+// ctx: Pulumi context.
+// name: The name of the software (used as the installation directory).
+// serverConfig: The server configuration.
+// conn: The remote connection arguments.
+// opts: Additional Pulumi resource options.
+func VerifyImages(
+	ctx *pulumi.Context,
+	name string,
+	serverConfig *server.Config,
+	conn *remote.ConnectionArgs,
+	opts ...pulumi.ResourceOption,
+) ([]pulumi.ResourceOption, pulumi.StringOutput, error) {
+	policies, pErr := verifyPolicies(serverConfig)
+	if pErr != nil {
+		return nil, pulumi.StringOutput{}, pErr
+	}
+	script, rErr := template.Render("./assets/install/verify-images.sh.j2", map[string]any{
+		"name":     name,
+		"policies": policies,
+	})
+	if rErr != nil {
+		return nil, pulumi.StringOutput{}, rErr
+	}
+
+	scriptFile := fmt.Sprintf("./outputs/%s_verify-images.sh", name)
+	scriptHash, _ := file.WritePulumi(scriptFile, pulumi.String(script)).
+		ApplyT(func(_ string) string {
+			hash, _ := file.Hash(scriptFile)
+			return *hash
+		}).(pulumi.StringOutput)
+	scriptCopy, scErr := remote.NewCopyToRemote(
+		ctx,
+		fmt.Sprintf("remote-copy-%s-verify-images", name),
+		&remote.CopyToRemoteArgs{
+			Source:     pulumi.NewFileAsset(scriptFile),
+			RemotePath: pulumi.Sprintf("/opt/%s/verify-images.sh", name),
+			Triggers:   pulumi.Array{scriptHash},
+			Connection: conn,
+		},
+		opts...)
+	if scErr != nil {
+		return nil, pulumi.StringOutput{}, scErr
+	}
+	opts = append(opts, pulumi.DependsOn([]pulumi.Resource{scriptCopy}))
+	return opts, scriptHash, nil
+}
+
+// verifyPolicies returns the repository patterns and cosign commands of the verification policies.
+// serverConfig: The server configuration.
+func verifyPolicies(serverConfig *server.Config) ([]map[string]string, error) {
+	policies := []map[string]string{}
+	if serverConfig.Images == nil || len(serverConfig.Images.Verify) == 0 {
+		return policies, nil
+	}
+
+	cosign := cosignImage
+	if serverUtil.PinDigests(serverConfig) {
+		var cErr error
+		if cosign, cErr = pinImage(cosignImage); cErr != nil {
+			return nil, cErr
+		}
+	}
+	for i := range serverConfig.Images.Verify {
+		policy := &serverConfig.Images.Verify[i]
+		policies = append(policies, map[string]string{
+			"repository": defaults.GetOrDefault(policy.Repository, ""),
+			"command":    cosignCommand(cosign, policy),
+		})
+	}
+	return policies, nil
+}
+
+// cosignCommand returns the command verifying an image with cosign according to a policy.
+// The image reference is appended as the last argument.
+// cosign: The image of cosign.
+// policy: The verification policy.
+func cosignCommand(cosign string, policy *server.ImageVerifyConfig) string {
+	args := []string{"docker", "run", "--rm"}
+	if policy.Key != nil {
+		args = append(args, "-e", "COSIGN_PUBLIC_KEY="+shell.Quote(*policy.Key))
+	}
+	args = append(args, cosign)
+
+	if policy.Attestation != nil {
+		args = append(args, "verify-attestation", "--type", shell.Quote(*policy.Attestation))
+	} else {
+		args = append(args, "verify")
+	}
+
+	if policy.Key != nil {
+		args = append(args, "--key", "env://COSIGN_PUBLIC_KEY")
+	} else {
+		args = append(args,
+			"--certificate-identity-regexp", shell.Quote(defaults.GetOrDefault(policy.Identity, "")),
+			"--certificate-oidc-issuer", shell.Quote(defaults.GetOrDefault(policy.Issuer, "")),
+		)
+	}
+	return strings.Join(args, " ")
+}
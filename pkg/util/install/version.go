@@ -1,11 +1,9 @@
 package install
 
 import (
-	"os"
 	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
-	"github.com/stretchr/testify/assert/yaml"
 )
 
 // Version reads the version of a service from a Docker Compose file.
@@ -14,22 +12,24 @@ import (
 // dockerComposeHash: Pulumi StringOutput representing the hash of the Docker Compose file.
 func Version(file string, service string, dockerComposeHash pulumi.Output) pulumi.StringOutput {
 	version, _ := dockerComposeHash.ApplyT(func(_ any) string {
-		data, rErr := os.ReadFile(file)
-		if rErr != nil {
-			return ""
-		}
+		return readImages(file)[service].Tag
+	}).(pulumi.StringOutput)
 
-		var parsed map[string]any
-		if pErr := yaml.Unmarshal(data, &parsed); pErr != nil {
-			return ""
-		}
+	return version
+}
 
-		v, ok := parsed["services"].(map[string]any)[service].(map[string]any)["image"].(string)
-		if !ok {
+// MajorVersion reads the major version of a service from a Docker Compose file (e.g. '18' of '18.6-alpine').
+// file: Path to the Docker Compose YAML file.
+// service: Name of the service whose major version is to be extracted.
+// dockerComposeHash: Pulumi StringOutput representing the hash of the Docker Compose file.
+func MajorVersion(file string, service string, dockerComposeHash pulumi.Output) pulumi.StringOutput {
+	return Version(file, service, dockerComposeHash).ApplyT(func(version string) string {
+		parts := strings.FieldsFunc(version, func(r rune) bool {
+			return r == '.' || r == '-'
+		})
+		if len(parts) == 0 {
 			return ""
 		}
-		return strings.Split(v, ":")[1]
+		return parts[0]
 	}).(pulumi.StringOutput)
-
-	return version
 }
@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/network"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+)
+
+// BackupMXEnabled returns whether the backup MX relay server is enabled.
+// mailConfig: Configuration related to mail services.
+func BackupMXEnabled(mailConfig *mailConf.Config) bool {
+	return mailConfig.BackupMX != nil && defaults.GetOrDefault(mailConfig.BackupMX.Enabled, false)
+}
+
+// BackupMXRecipients returns the declared recipients accepted by the backup MX relay server:
+// the managed mailboxes and aliases of the mail domains, and the postmaster of every mail domain.
+// The recipients exported by mailcow and SimpleLogin are fetched by the backup MX relay server itself.
+// mailConfig: Configuration related to mail services.
+func BackupMXRecipients(mailConfig *mailConf.Config) []string {
+	domains := Domains(mailConfig)
+	recipients := []string{}
+	for _, domain := range domains {
+		recipients = append(recipients, "postmaster@"+domain)
+	}
+	for _, mailbox := range Mailboxes(mailConfig) {
+		recipients = append(recipients, *mailbox.Address)
+	}
+	if mailConfig.Mailcow != nil {
+		for _, alias := range mailConfig.Mailcow.Aliases {
+			_, domain, aErr := SplitAddress(*alias.Address)
+			if aErr == nil && slices.Contains(domains, domain) {
+				recipients = append(recipients, *alias.Address)
+			}
+		}
+	}
+	slices.Sort(recipients)
+	return slices.Compact(recipients)
+}
+
+// ValidateBackupMX validates the backup MX relay server: it requires its own server in another location
+// than the primary server, with an IPv4 address within the subnet.
+// mailConfig: Configuration related to mail services.
+// serverConfig: The configuration of the primary server.
+// networkConfig: The network configuration.
+func ValidateBackupMX(mailConfig *mailConf.Config, serverConfig *server.Config, networkConfig *network.Config) error {
+	if !BackupMXEnabled(mailConfig) {
+		return nil
+	}
+
+	backup := mailConfig.BackupMX.Server
+	if backup == nil || backup.Location == nil || backup.Type == nil || backup.IPv4 == nil {
+		return fmt.Errorf("backup MX requires a server with location, type, and ipv4")
+	}
+	if serverConfig.Location != nil && *backup.Location == *serverConfig.Location {
+		return fmt.Errorf("backup MX server must be in another location than the primary server (%s)", *backup.Location)
+	}
+
+	address, aErr := netip.ParseAddr(*backup.IPv4)
+	if aErr != nil || !address.Is4() {
+		return fmt.Errorf("backup MX server ipv4 %s is not a valid IPv4 address", *backup.IPv4)
+	}
+	if serverConfig.IPv4 != nil && *backup.IPv4 == *serverConfig.IPv4 {
+		return fmt.Errorf("backup MX server ipv4 %s is already used by the primary server", *backup.IPv4)
+	}
+	if networkConfig.SubnetCIDR == nil {
+		return fmt.Errorf("backup MX server requires the subnet CIDR of the network")
+	}
+	subnet, sErr := netip.ParsePrefix(*networkConfig.SubnetCIDR)
+	if sErr != nil {
+		return fmt.Errorf("network subnet CIDR %s is invalid: %w", *networkConfig.SubnetCIDR, sErr)
+	}
+	if !subnet.Contains(address) {
+		return fmt.Errorf("backup MX server ipv4 %s is not within the subnet %s", *backup.IPv4, subnet)
+	}
+	return nil
+}
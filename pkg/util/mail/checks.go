@@ -0,0 +1,141 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+)
+
+// checkPatternPattern matches a PCRE lookup table pattern with its delimiters and flags.
+var checkPatternPattern = regexp.MustCompile(`^!?/(.+)/([imsxAEUX]*)$`)
+
+// unescapedDelimiterPattern matches an unescaped '/' within a PCRE pattern.
+var unescapedDelimiterPattern = regexp.MustCompile(`(^|[^\\])(\\\\)*/`)
+
+// BodyChecks returns the body check rules, falling back to the default rules if none are configured.
+// mailConfig: Configuration related to mail services.
+func BodyChecks(mailConfig *mailConf.Config) []*mailConf.CheckRule {
+	if mailConfig.Postfix != nil && mailConfig.Postfix.BodyChecks != nil {
+		return mailConfig.Postfix.BodyChecks
+	}
+	return []*mailConf.CheckRule{
+		{
+			Pattern: stringPtr("/^X-SimpleLogin-Client-IP:/"),
+			Action:  stringPtr("IGNORE"),
+			Comment: stringPtr("remove spoofed SimpleLogin client IP headers"),
+		},
+	}
+}
+
+// ClientChecks returns the client restriction rules, falling back to the default rules if none are configured.
+// mailConfig: Configuration related to mail services.
+func ClientChecks(mailConfig *mailConf.Config) []*mailConf.CheckRule {
+	if mailConfig.Postfix != nil && mailConfig.Postfix.ClientChecks != nil {
+		return mailConfig.Postfix.ClientChecks
+	}
+	return []*mailConf.CheckRule{
+		{
+			Pattern: stringPtr("/^([0-9a-f:.]+)$/"),
+			Action:  stringPtr("prepend X-SimpleLogin-Client-IP: $1"),
+			Comment: stringPtr("pass the client IP address to SimpleLogin"),
+		},
+	}
+}
+
+// FilterCheckRules returns the rules applying to the given environment, preserving their order.
+// rules: The check rules.
+// environment: The deployment environment.
+func FilterCheckRules(rules []*mailConf.CheckRule, environment string) []*mailConf.CheckRule {
+	filtered := []*mailConf.CheckRule{}
+	for _, rule := range rules {
+		if len(rule.Environments) == 0 || slices.Contains(rule.Environments, environment) {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
+
+// ValidateCheckRules validates the body check and client restriction rules.
+// The patterns are compiled on a best effort basis, and validated server-side by Postfix before deployment.
+// mailConfig: Configuration related to mail services.
+func ValidateCheckRules(mailConfig *mailConf.Config) error {
+	for i, rule := range BodyChecks(mailConfig) {
+		if vErr := validateCheckRule(rule); vErr != nil {
+			return fmt.Errorf("body check rule %d is invalid: %w", i, vErr)
+		}
+	}
+	for i, rule := range ClientChecks(mailConfig) {
+		if vErr := validateCheckRule(rule); vErr != nil {
+			return fmt.Errorf("client restriction rule %d is invalid: %w", i, vErr)
+		}
+	}
+	return nil
+}
+
+// validateCheckRule validates a single check rule.
+// rule: The check rule.
+func validateCheckRule(rule *mailConf.CheckRule) error {
+	if rule.Pattern == nil || rule.Action == nil || *rule.Action == "" {
+		return fmt.Errorf("pattern and action are required")
+	}
+	for _, value := range []*string{rule.Pattern, rule.Action, rule.Comment} {
+		if value != nil && strings.ContainsAny(*value, "\r\n") {
+			return fmt.Errorf("values must not contain line breaks")
+		}
+	}
+
+	matches := checkPatternPattern.FindStringSubmatch(*rule.Pattern)
+	if matches == nil {
+		return fmt.Errorf("pattern %s must be enclosed in '/' delimiters with optional flags", *rule.Pattern)
+	}
+	if unescapedDelimiterPattern.MatchString(matches[1]) {
+		return fmt.Errorf("pattern %s contains an unescaped '/' delimiter", *rule.Pattern)
+	}
+
+	return compileCheckPattern(matches[1], matches[2])
+}
+
+// compileCheckPattern compiles a PCRE pattern as RE2 on a best effort basis.
+// Syntax errors which are errors in PCRE too are reported; PCRE features unsupported by RE2 (e.g. lookarounds,
+// backreferences, extended mode) are left to the validation by Postfix on the server.
+// pattern: The pattern without delimiters.
+// flags: The PCRE flags.
+func compileCheckPattern(pattern string, flags string) error {
+	if strings.ContainsAny(flags, "xAEUX") {
+		return nil
+	}
+
+	re2Flags := ""
+	for _, flag := range []string{"i", "m", "s"} {
+		if strings.Contains(flags, flag) {
+			re2Flags += flag
+		}
+	}
+	if re2Flags != "" {
+		pattern = fmt.Sprintf("(?%s)%s", re2Flags, pattern)
+	}
+
+	_, cErr := syntax.Parse(pattern, syntax.Perl)
+	var syntaxErr *syntax.Error
+	if cErr == nil || !errors.As(cErr, &syntaxErr) {
+		return cErr
+	}
+	switch syntaxErr.Code {
+	case syntax.ErrMissingBracket, syntax.ErrMissingParen, syntax.ErrUnexpectedParen,
+		syntax.ErrMissingRepeatArgument, syntax.ErrTrailingBackslash, syntax.ErrInvalidCharRange:
+		return fmt.Errorf("pattern is not a valid regular expression: %w", cErr)
+	default:
+		return nil
+	}
+}
+
+// stringPtr returns a pointer to the given string.
+// value: The string value.
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,13 @@
+package mail
+
+import mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+
+// Domains returns the names of the main and all additional mail domains.
+// mailConfig: Configuration related to mail services.
+func Domains(mailConfig *mailConf.Config) []string {
+	domains := []string{*mailConfig.Main.Name}
+	for _, domain := range mailConfig.Additional {
+		domains = append(domains, *domain.Name)
+	}
+	return domains
+}
@@ -7,3 +7,9 @@ import "fmt"
 func Mailname(domain string) string {
 	return fmt.Sprintf("mail.%s", domain)
 }
+
+// BackupMailname constructs the backup MX server name for the given domain.
+// domain: The domain for which to create the backup MX server name.
+func BackupMailname(domain string) string {
+	return fmt.Sprintf("mx2.%s", domain)
+}
@@ -0,0 +1,92 @@
+package mail
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+)
+
+// rateLimitFrames is the list of valid rate limit frames.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var rateLimitFrames = []string{"s", "m", "h", "d"}
+
+// SplitAddress splits an e-mail address into its local part and domain.
+// address: The e-mail address.
+func SplitAddress(address string) (string, string, error) {
+	localPart, domain, found := strings.Cut(address, "@")
+	if !found || localPart == "" || domain == "" || strings.Contains(domain, "@") {
+		return "", "", fmt.Errorf("%s is not a valid e-mail address", address)
+	}
+	return localPart, domain, nil
+}
+
+// ValidateMailcowObjects validates the objects managed in mailcow.
+// mailConfig: Configuration related to mail services.
+//
+//nolint:gocognit // validation of all object types is kept together
+func ValidateMailcowObjects(mailConfig *mailConf.Config) error {
+	if mailConfig.Mailcow == nil {
+		return nil
+	}
+
+	domains := Domains(mailConfig)
+	aliasDomains := []string{}
+	for _, domainAlias := range mailConfig.Mailcow.DomainAliases {
+		if domainAlias.Alias == nil || domainAlias.Target == nil {
+			return fmt.Errorf("mailcow domain alias requires the alias and the target")
+		}
+		if slices.Contains(domains, *domainAlias.Alias) {
+			return fmt.Errorf("mailcow domain alias %s must not be a mail domain", *domainAlias.Alias)
+		}
+		if !slices.Contains(domains, *domainAlias.Target) {
+			return fmt.Errorf("mailcow domain alias %s targets the unmanaged domain %s", *domainAlias.Alias, *domainAlias.Target)
+		}
+		aliasDomains = append(aliasDomains, *domainAlias.Alias)
+	}
+
+	configured := map[string]bool{}
+	for _, domain := range mailConfig.Mailcow.Domains {
+		if domain.Name == nil || !slices.Contains(domains, *domain.Name) {
+			return fmt.Errorf("mailcow domain settings must reference a mail domain")
+		}
+		if configured[*domain.Name] {
+			return fmt.Errorf("mailcow domain %s is configured more than once", *domain.Name)
+		}
+		configured[*domain.Name] = true
+		if domain.RateLimit != nil && domain.RateLimit.Frame != nil &&
+			!slices.Contains(rateLimitFrames, *domain.RateLimit.Frame) {
+			return fmt.Errorf("mailcow domain %s has the invalid rate limit frame %s", *domain.Name, *domain.RateLimit.Frame)
+		}
+	}
+
+	for _, mailbox := range mailConfig.Mailcow.Mailboxes {
+		if mailbox.Address == nil {
+			return fmt.Errorf("mailcow mailbox is missing the address")
+		}
+		_, domain, aErr := SplitAddress(*mailbox.Address)
+		if aErr != nil {
+			return aErr
+		}
+		if !slices.Contains(domains, domain) {
+			return fmt.Errorf("mailcow mailbox %s belongs to the unmanaged domain %s", *mailbox.Address, domain)
+		}
+	}
+
+	for _, alias := range mailConfig.Mailcow.Aliases {
+		if alias.Address == nil || len(alias.Goto) == 0 {
+			return fmt.Errorf("mailcow alias requires the address and at least one destination")
+		}
+		_, domain, aErr := SplitAddress(*alias.Address)
+		if aErr != nil {
+			return aErr
+		}
+		if !slices.Contains(domains, domain) && !slices.Contains(aliasDomains, domain) {
+			return fmt.Errorf("mailcow alias %s belongs to the unmanaged domain %s", *alias.Address, domain)
+		}
+	}
+
+	return nil
+}
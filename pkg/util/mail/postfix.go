@@ -0,0 +1,87 @@
+package mail
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+)
+
+// postfixParameterPattern matches valid Postfix parameter names.
+var postfixParameterPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// allowedPostfixParameterPrefixes is the list of prefixes of Postfix parameters which can be set from configuration.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var allowedPostfixParameterPrefixes = []string{"smtpd_tls_", "postscreen_"}
+
+// ValidatePostfix validates the additional Postfix configuration.
+// mailConfig: Configuration related to mail services.
+func ValidatePostfix(mailConfig *mailConf.Config) error {
+	if mailConfig.Postfix == nil {
+		return nil
+	}
+
+	if mailConfig.Postfix.MessageSizeLimit != nil && *mailConfig.Postfix.MessageSizeLimit <= 0 {
+		return fmt.Errorf("postfix message size limit must be positive, got %d", *mailConfig.Postfix.MessageSizeLimit)
+	}
+
+	for _, network := range mailConfig.Postfix.MyNetworks {
+		if _, pErr := netip.ParsePrefix(network); pErr != nil {
+			return fmt.Errorf("postfix network %s is not a valid CIDR: %w", network, pErr)
+		}
+	}
+
+	for key, value := range mailConfig.Postfix.Parameters {
+		if !postfixParameterPattern.MatchString(key) {
+			return fmt.Errorf("postfix parameter %s is not a valid parameter name", key)
+		}
+		if !hasAllowedPostfixPrefix(key) {
+			return fmt.Errorf(
+				"postfix parameter %s is not allowed, only parameters prefixed with %s can be set",
+				key,
+				strings.Join(allowedPostfixParameterPrefixes, ", "),
+			)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("postfix parameter %s must not contain line breaks", key)
+		}
+	}
+
+	return nil
+}
+
+// PostfixNetworks returns the additional trusted networks in Postfix notation (IPv6 networks enclosed in brackets).
+// mailConfig: Configuration related to mail services.
+func PostfixNetworks(mailConfig *mailConf.Config) []string {
+	if mailConfig.Postfix == nil {
+		return nil
+	}
+
+	networks := []string{}
+	for _, network := range mailConfig.Postfix.MyNetworks {
+		prefix, pErr := netip.ParsePrefix(network)
+		if pErr != nil {
+			continue
+		}
+		if prefix.Addr().Is6() {
+			networks = append(networks, fmt.Sprintf("[%s]/%d", prefix.Addr(), prefix.Bits()))
+			continue
+		}
+		networks = append(networks, prefix.String())
+	}
+	return networks
+}
+
+// hasAllowedPostfixPrefix checks if the Postfix parameter has an allowed prefix.
+// key: The Postfix parameter name.
+func hasAllowedPostfixPrefix(key string) bool {
+	for _, prefix := range allowedPostfixParameterPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	mailcowConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mailcow"
+)
+
+// defaultProbeNtfyTopic is the default Ntfy topic of the probe outcome.
+const defaultProbeNtfyTopic = "mail-probe"
+
+// ProbeEnabled returns whether the end-to-end mail flow probe runs.
+// mailConfig: Configuration related to mail services.
+func ProbeEnabled(mailConfig *mailConf.Config) bool {
+	return mailConfig.Probe != nil && defaults.GetOrDefault(mailConfig.Probe.Enabled, false)
+}
+
+// ProbeMailbox returns the address of the probe mailbox.
+// mailConfig: Configuration related to mail services.
+func ProbeMailbox(mailConfig *mailConf.Config) string {
+	fallback := fmt.Sprintf("probe@%s", *mailConfig.Main.Name)
+	if mailConfig.Probe == nil {
+		return fallback
+	}
+	return defaults.GetOrDefault(mailConfig.Probe.Mailbox, fallback)
+}
+
+// ProbeAlias returns the SimpleLogin alias forwarding to the probe mailbox, or an empty string if none is configured.
+// mailConfig: Configuration related to mail services.
+func ProbeAlias(mailConfig *mailConf.Config) string {
+	if mailConfig.Probe == nil {
+		return ""
+	}
+	return defaults.GetOrDefault(mailConfig.Probe.Alias, "")
+}
+
+// ProbeRun returns the identifier of the probe run, or an empty string if none is configured.
+// mailConfig: Configuration related to mail services.
+func ProbeRun(mailConfig *mailConf.Config) string {
+	if mailConfig.Probe == nil {
+		return ""
+	}
+	return defaults.GetOrDefault(mailConfig.Probe.Run, "")
+}
+
+// ProbeNtfyEnabled returns whether the probe outcome is pushed to Ntfy.
+// mailConfig: Configuration related to mail services.
+func ProbeNtfyEnabled(mailConfig *mailConf.Config) bool {
+	return ProbeEnabled(mailConfig) && mailConfig.Probe.Ntfy != nil &&
+		defaults.GetOrDefault(mailConfig.Probe.Ntfy.Enabled, false)
+}
+
+// ProbeNtfyTopic returns the Ntfy topic of the probe outcome.
+// mailConfig: Configuration related to mail services.
+func ProbeNtfyTopic(mailConfig *mailConf.Config) string {
+	if mailConfig.Probe == nil || mailConfig.Probe.Ntfy == nil {
+		return defaultProbeNtfyTopic
+	}
+	return defaults.GetOrDefault(mailConfig.Probe.Ntfy.Topic, defaultProbeNtfyTopic)
+}
+
+// Mailboxes returns the mailboxes managed in mailcow, including the probe mailbox if the probe runs.
+// mailConfig: Configuration related to mail services.
+func Mailboxes(mailConfig *mailConf.Config) []*mailcowConf.MailboxConfig {
+	mailboxes := []*mailcowConf.MailboxConfig{}
+	if mailConfig.Mailcow != nil {
+		mailboxes = append(mailboxes, mailConfig.Mailcow.Mailboxes...)
+	}
+	if !ProbeEnabled(mailConfig) {
+		return mailboxes
+	}
+
+	probe := ProbeMailbox(mailConfig)
+	if slices.ContainsFunc(mailboxes, func(mailbox *mailcowConf.MailboxConfig) bool {
+		return mailbox.Address != nil && *mailbox.Address == probe
+	}) {
+		return mailboxes
+	}
+	name := "Mail Probe"
+	return append(mailboxes, &mailcowConf.MailboxConfig{Address: &probe, Name: &name})
+}
+
+// ValidateProbe validates the end-to-end mail flow probe.
+// mailConfig: Configuration related to mail services.
+func ValidateProbe(mailConfig *mailConf.Config) error {
+	if !ProbeEnabled(mailConfig) {
+		return nil
+	}
+
+	mailbox := ProbeMailbox(mailConfig)
+	_, domain, aErr := SplitAddress(mailbox)
+	if aErr != nil || strings.ContainsAny(mailbox, " '\"\\$`") {
+		return fmt.Errorf("probe mailbox %s is invalid", mailbox)
+	}
+	if !slices.Contains(Domains(mailConfig), domain) {
+		return fmt.Errorf("probe mailbox %s belongs to the unmanaged domain %s", mailbox, domain)
+	}
+	if alias := ProbeAlias(mailConfig); alias != "" {
+		if _, _, alErr := SplitAddress(alias); alErr != nil || strings.ContainsAny(alias, " '\"\\$`") {
+			return fmt.Errorf("probe alias %s is invalid", alias)
+		}
+	}
+	if topic := ProbeNtfyTopic(mailConfig); !ntfyTopicPattern.MatchString(topic) {
+		return fmt.Errorf("probe Ntfy topic %s is invalid", topic)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+)
+
+// retentionNamePattern matches valid names of retention policies.
+var retentionNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// dovecotIntervalPattern matches valid Dovecot time intervals.
+var dovecotIntervalPattern = regexp.MustCompile(
+	`^[1-9][0-9]*(s|secs?|seconds?|mins?|minutes?|h|hours?|d|days?|w|weeks?)$`,
+)
+
+// ofeliaDescriptorPattern matches valid ofelia schedule descriptors.
+var ofeliaDescriptorPattern = regexp.MustCompile(
+	`^@(yearly|annually|monthly|weekly|daily|midnight|hourly|every [0-9]+(ms|s|m|h)([0-9]+(ms|s|m))*)$`,
+)
+
+// ofeliaCronFieldPattern matches a valid field of an ofelia cron schedule.
+var ofeliaCronFieldPattern = regexp.MustCompile(`^[0-9A-Za-z*?/,-]+$`)
+
+// ofeliaCronFields is the number of fields of an ofelia cron schedule (including seconds).
+const ofeliaCronFields = 6
+
+// RetentionPolicies returns the retention policies, falling back to the default policies if none are configured.
+// mailConfig: Configuration related to mail services.
+func RetentionPolicies(mailConfig *mailConf.Config) []*mailConf.RetentionPolicy {
+	if mailConfig.Retention != nil {
+		return mailConfig.Retention
+	}
+	return []*mailConf.RetentionPolicy{
+		{
+			Name:     stringPtr("trash"),
+			Mailbox:  stringPtr("Trash"),
+			Age:      stringPtr("4w"),
+			Schedule: stringPtr("0 0 3 * * *"),
+		},
+		{
+			Name:     stringPtr("junk"),
+			Mailbox:  stringPtr("Junk"),
+			Age:      stringPtr("4w"),
+			Schedule: stringPtr("0 0 4 * * *"),
+		},
+	}
+}
+
+// RetentionJobs returns the ofelia jobs of all retention policies.
+// Each job has a name, a schedule, and the doveadm command to run.
+// mailConfig: Configuration related to mail services.
+func RetentionJobs(mailConfig *mailConf.Config) []map[string]string {
+	jobs := []map[string]string{}
+	for _, policy := range RetentionPolicies(mailConfig) {
+		if len(policy.Domains) == 0 {
+			jobs = append(jobs, map[string]string{
+				"name":     fmt.Sprintf("dovecot-expunge-%s", *policy.Name),
+				"schedule": *policy.Schedule,
+				"command":  fmt.Sprintf("doveadm expunge -A mailbox '%s' savedbefore %s", *policy.Mailbox, *policy.Age),
+			})
+			continue
+		}
+
+		for _, domain := range policy.Domains {
+			jobs = append(jobs, map[string]string{
+				"name":     fmt.Sprintf("dovecot-expunge-%s-%s", *policy.Name, strings.ReplaceAll(domain, ".", "-")),
+				"schedule": *policy.Schedule,
+				"command": fmt.Sprintf(
+					"doveadm expunge -u '*@%s' mailbox '%s' savedbefore %s",
+					domain,
+					*policy.Mailbox,
+					*policy.Age,
+				),
+			})
+		}
+	}
+	return jobs
+}
+
+// ValidateRetention validates the retention policies.
+// mailConfig: Configuration related to mail services.
+func ValidateRetention(mailConfig *mailConf.Config) error {
+	domains := Domains(mailConfig)
+	names := map[string]bool{}
+	for _, policy := range RetentionPolicies(mailConfig) {
+		if policy.Name == nil || !retentionNamePattern.MatchString(*policy.Name) || names[*policy.Name] {
+			return fmt.Errorf("retention policies require a unique name of lowercase letters, digits, and dashes")
+		}
+		names[*policy.Name] = true
+
+		if policy.Mailbox == nil || *policy.Mailbox == "" || strings.ContainsAny(*policy.Mailbox, "'\"\\\r\n") {
+			return fmt.Errorf("retention policy %s requires a mailbox without quotes or line breaks", *policy.Name)
+		}
+		if policy.Age == nil || !dovecotIntervalPattern.MatchString(*policy.Age) {
+			return fmt.Errorf("retention policy %s requires a Dovecot time interval like '4w' or '30d'", *policy.Name)
+		}
+		if policy.Schedule == nil || !validOfeliaSchedule(*policy.Schedule) {
+			return fmt.Errorf("retention policy %s requires a schedule like '0 0 3 * * *' or '@daily'", *policy.Name)
+		}
+		for _, domain := range policy.Domains {
+			if !slices.Contains(domains, domain) {
+				return fmt.Errorf("retention policy %s references the unknown domain %s", *policy.Name, domain)
+			}
+		}
+	}
+	return nil
+}
+
+// validOfeliaSchedule returns whether the schedule is a valid ofelia cron schedule or descriptor.
+// schedule: The schedule.
+func validOfeliaSchedule(schedule string) bool {
+	if ofeliaDescriptorPattern.MatchString(schedule) {
+		return true
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != ofeliaCronFields {
+		return false
+	}
+	for _, field := range fields {
+		if !ofeliaCronFieldPattern.MatchString(field) {
+			return false
+		}
+	}
+	return true
+}
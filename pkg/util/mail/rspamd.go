@@ -0,0 +1,136 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+)
+
+// rspamdNamePattern matches valid names of rspamd lists and rate limits.
+var rspamdNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// rspamdTimePattern matches valid rspamd time intervals.
+var rspamdTimePattern = regexp.MustCompile(`^[0-9]+(s|min|h|d|w)?$`)
+
+// rspamdRatePattern matches valid rspamd rate limit rates.
+var rspamdRatePattern = regexp.MustCompile(`^[0-9]+ / [0-9]*(s|m|min|h|d)$`)
+
+// arcSelectorPattern matches valid DNS selectors of the ARC keys.
+var arcSelectorPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// defaultARCSelector is the default DNS selector of the ARC keys.
+const defaultARCSelector = "arc"
+
+// rspamdMultimapTypes is the list of supported rspamd multimap types.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var rspamdMultimapTypes = []string{"from", "rcpt", "ip", "received", "header", "selector", "url", "content"}
+
+// rspamdActions is the list of actions an rspamd multimap can force.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var rspamdActions = []string{"accept", "reject", "add header", "rewrite subject", "soft reject", "no action", "greylist"}
+
+// RspamdSymbol returns the rspamd symbol of a custom multimap list.
+// name: The name of the list.
+func RspamdSymbol(name string) string {
+	return fmt.Sprintf("CUSTOM_%s", strings.ToUpper(name))
+}
+
+// ARCEnabled returns whether forwarded mail is signed and sealed with ARC.
+// mailConfig: Configuration related to mail services.
+func ARCEnabled(mailConfig *mailConf.Config) bool {
+	return mailConfig.Rspamd != nil && mailConfig.Rspamd.ARC != nil &&
+		mailConfig.Rspamd.ARC.Enabled != nil && *mailConfig.Rspamd.ARC.Enabled
+}
+
+// ARCSelector returns the DNS selector of the ARC keys.
+// mailConfig: Configuration related to mail services.
+func ARCSelector(mailConfig *mailConf.Config) string {
+	if mailConfig.Rspamd == nil || mailConfig.Rspamd.ARC == nil || mailConfig.Rspamd.ARC.Selector == nil {
+		return defaultARCSelector
+	}
+	return *mailConfig.Rspamd.ARC.Selector
+}
+
+// ValidateRspamd validates the rspamd overrides.
+// mailConfig: Configuration related to mail services.
+//
+//nolint:gocognit // validation of all override types is kept together
+func ValidateRspamd(mailConfig *mailConf.Config) error {
+	if mailConfig.Rspamd == nil {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, list := range mailConfig.Rspamd.Multimap {
+		// the map files and symbols are derived case-insensitively from the names
+		if list.Name == nil || !rspamdNamePattern.MatchString(*list.Name) || names[strings.ToLower(*list.Name)] {
+			return fmt.Errorf(
+				"rspamd multimap lists require a unique name (case-insensitive) of letters, digits, and underscores",
+			)
+		}
+		names[strings.ToLower(*list.Name)] = true
+
+		if list.Type == nil || !slices.Contains(rspamdMultimapTypes, *list.Type) {
+			return fmt.Errorf("rspamd multimap list %s has an unsupported type", *list.Name)
+		}
+		if (list.Action == nil) == (list.Score == nil) {
+			return fmt.Errorf("rspamd multimap list %s requires either an action or a score", *list.Name)
+		}
+		if list.Action != nil && !slices.Contains(rspamdActions, *list.Action) {
+			return fmt.Errorf("rspamd multimap list %s has the unsupported action %s", *list.Name, *list.Action)
+		}
+		for _, value := range append([]string{
+			valueOrEmpty(list.Header), valueOrEmpty(list.Filter), valueOrEmpty(list.Description),
+		}, list.Entries...) {
+			if strings.ContainsAny(value, "\"\r\n") {
+				return fmt.Errorf("rspamd multimap list %s must not contain quotes or line breaks", *list.Name)
+			}
+		}
+	}
+
+	if greylisting := mailConfig.Rspamd.Greylisting; greylisting != nil {
+		for _, interval := range []*string{greylisting.Timeout, greylisting.Expire} {
+			if interval != nil && !rspamdTimePattern.MatchString(*interval) {
+				return fmt.Errorf("rspamd greylisting interval %s is invalid", *interval)
+			}
+		}
+	}
+
+	if selector := ARCSelector(mailConfig); !arcSelectorPattern.MatchString(selector) || selector == "dkim" {
+		return fmt.Errorf("rspamd ARC selector %s is invalid or collides with the DKIM selector", selector)
+	}
+
+	names = map[string]bool{}
+	for _, limit := range mailConfig.Rspamd.RateLimits {
+		if limit.Name == nil || !rspamdNamePattern.MatchString(*limit.Name) || names[*limit.Name] {
+			return fmt.Errorf("rspamd rate limits require a unique name of letters, digits, and underscores")
+		}
+		names[*limit.Name] = true
+
+		if limit.Selector == nil || strings.ContainsAny(*limit.Selector, "'\r\n") {
+			return fmt.Errorf("rspamd rate limit %s requires a valid selector", *limit.Name)
+		}
+		if limit.Burst == nil || *limit.Burst <= 0 {
+			return fmt.Errorf("rspamd rate limit %s requires a positive burst", *limit.Name)
+		}
+		if limit.Rate == nil || !rspamdRatePattern.MatchString(*limit.Rate) {
+			return fmt.Errorf("rspamd rate limit %s requires a rate like '10 / 1min'", *limit.Name)
+		}
+	}
+
+	return nil
+}
+
+// valueOrEmpty returns the value of the pointer or an empty string.
+// value: The pointer.
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
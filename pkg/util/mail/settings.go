@@ -0,0 +1,210 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	mailcowConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mailcow"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+)
+
+// timezonePattern matches valid IANA timezone names.
+var timezonePattern = regexp.MustCompile(`^[A-Za-z]+(/[A-Za-z0-9_+-]+)*$`)
+
+// sanPattern matches valid additional certificate names.
+var sanPattern = regexp.MustCompile(`^(\*|[a-z0-9-]+)(\.(\*|[a-z0-9-]+))*$`)
+
+// ntfyTopicPattern matches valid Ntfy topic names.
+var ntfyTopicPattern = regexp.MustCompile(`^[-_A-Za-z0-9]{1,64}$`)
+
+// defaultWatchdogNtfyTopic is the default Ntfy topic of the watchdog notifications.
+const defaultWatchdogNtfyTopic = "mailcow-watchdog"
+
+// mailcowPreset defines the resource dependent switches of a preset.
+type mailcowPreset struct {
+	// skipClamd indicates if ClamAV is disabled.
+	skipClamd bool
+	// skipFTS indicates if the full-text search is disabled.
+	skipFTS bool
+	// ftsHeap is the maximal heap size of the full-text search indexer in MB.
+	ftsHeap int
+	// ftsProcs is the maximal number of full-text search indexer processes.
+	ftsProcs int
+}
+
+// mailcowPresets maps the resource presets to their switches.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var mailcowPresets = map[string]mailcowPreset{
+	// up to 4 GB memory: neither ClamAV nor the full-text search fit
+	"small": {skipClamd: true, skipFTS: true, ftsHeap: 128, ftsProcs: 1},
+	// 8 GB memory: full-text search without ClamAV
+	"medium": {skipClamd: true, skipFTS: false, ftsHeap: 128, ftsProcs: 1},
+	// 16 GB memory and more: all services
+	"large": {skipClamd: false, skipFTS: false, ftsHeap: 512, ftsProcs: 2},
+}
+
+// passwordSchemes is the list of password hashing schemes supported by mailcow.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var passwordSchemes = []string{"BLF-CRYPT", "SSHA256", "SSHA512", "ARGON2I", "ARGON2ID"}
+
+// MailcowPreset returns the resource preset of the mailcow configuration.
+// The configured preset takes precedence over the preset of the server type.
+// mailConfig: Configuration related to mail services.
+// serverType: The Hetzner cloud server type.
+func MailcowPreset(mailConfig *mailConf.Config, serverType string) string {
+	settings := mailcowSettings(mailConfig)
+	if settings.Preset != nil {
+		return *settings.Preset
+	}
+	return serverUtil.Preset(serverType)
+}
+
+// MailcowSettings returns the values of the mailcow configuration switches.
+// The configured switches take precedence over the resource preset.
+// mailConfig: Configuration related to mail services.
+// serverType: The Hetzner cloud server type.
+func MailcowSettings(mailConfig *mailConf.Config, serverType string) map[string]any {
+	settings := mailcowSettings(mailConfig)
+	preset := mailcowPresets[MailcowPreset(mailConfig, serverType)]
+
+	sogo := settings.SOGo
+	if sogo == nil {
+		sogo = &mailcowConf.SOGoSettings{}
+	}
+	watchdog := settings.Watchdog
+	if watchdog == nil {
+		watchdog = &mailcowConf.WatchdogSettings{}
+	}
+
+	return map[string]any{
+		"skipClamd":      yesNo(defaults.GetOrDefault(settings.SkipClamd, preset.skipClamd)),
+		"skipFts":        yesNo(defaults.GetOrDefault(settings.SkipFTS, preset.skipFTS)),
+		"ftsHeap":        defaults.GetOrDefault(settings.FTSHeap, preset.ftsHeap),
+		"ftsProcs":       defaults.GetOrDefault(settings.FTSProcs, preset.ftsProcs),
+		"maildirGcTime":  defaults.GetOrDefault(settings.MaildirGCTime, 7200),
+		"timezone":       defaults.GetOrDefault(settings.Timezone, "Etc/UTC"),
+		"additionalSan":  strings.Join(append([]string{"mail.*", "mta-sts.*"}, settings.AdditionalSAN...), ","),
+		"passwordScheme": defaults.GetOrDefault(settings.PasswordScheme, "BLF-CRYPT"),
+		"sogo": map[string]any{
+			"skip":                 yesNo(defaults.GetOrDefault(sogo.Skip, false)),
+			"expireSession":        defaults.GetOrDefault(sogo.ExpireSession, 120),
+			"allowAdminEmailLogin": yesNo(defaults.GetOrDefault(sogo.AllowAdminEmailLogin, false)),
+		},
+		"watchdog": map[string]any{
+			"enabled":        yesNo(defaults.GetOrDefault(watchdog.Enabled, true)),
+			"notifyBan":      yesNo(defaults.GetOrDefault(watchdog.NotifyBan, false)),
+			"notifyStart":    yesNo(defaults.GetOrDefault(watchdog.NotifyStart, false)),
+			"externalChecks": yesNo(defaults.GetOrDefault(watchdog.ExternalChecks, false)),
+			"verbose":        yesNo(defaults.GetOrDefault(watchdog.Verbose, false)),
+			"notifyEmail":    strings.Join(watchdog.NotifyEmail, ","),
+			"subject":        defaults.GetOrDefault(watchdog.Subject, ""),
+		},
+	}
+}
+
+// ValidateMailcowSettings validates the switches of the mailcow configuration.
+// mailConfig: Configuration related to mail services.
+func ValidateMailcowSettings(mailConfig *mailConf.Config) error {
+	settings := mailcowSettings(mailConfig)
+
+	if settings.Preset != nil {
+		if _, ok := mailcowPresets[*settings.Preset]; !ok {
+			return fmt.Errorf("mailcow preset %s is invalid; use 'small', 'medium', or 'large'", *settings.Preset)
+		}
+	}
+	for _, value := range []*int{settings.FTSHeap, settings.FTSProcs, settings.MaildirGCTime} {
+		if value != nil && *value <= 0 {
+			return fmt.Errorf("mailcow settings require positive sizes, counts, and times")
+		}
+	}
+	if settings.SOGo != nil && settings.SOGo.ExpireSession != nil && *settings.SOGo.ExpireSession <= 0 {
+		return fmt.Errorf("mailcow SOGo session timeout must be positive")
+	}
+	if settings.Timezone != nil && !timezonePattern.MatchString(*settings.Timezone) {
+		return fmt.Errorf("mailcow timezone %s is invalid", *settings.Timezone)
+	}
+	for _, san := range settings.AdditionalSAN {
+		if !sanPattern.MatchString(san) {
+			return fmt.Errorf("mailcow additional certificate name %s is invalid", san)
+		}
+	}
+	if settings.PasswordScheme != nil && !slices.Contains(passwordSchemes, *settings.PasswordScheme) {
+		return fmt.Errorf("mailcow password scheme %s is not supported", *settings.PasswordScheme)
+	}
+
+	return validateWatchdogSettings(settings.Watchdog)
+}
+
+// WatchdogNtfyEnabled returns whether the watchdog notifies the Ntfy topic.
+// mailConfig: Configuration related to mail services.
+func WatchdogNtfyEnabled(mailConfig *mailConf.Config) bool {
+	watchdog := mailcowSettings(mailConfig).Watchdog
+	return watchdog != nil && watchdog.Ntfy != nil && defaults.GetOrDefault(watchdog.Ntfy.Enabled, false)
+}
+
+// WatchdogNtfyTopic returns the Ntfy topic of the watchdog notifications.
+// mailConfig: Configuration related to mail services.
+func WatchdogNtfyTopic(mailConfig *mailConf.Config) string {
+	watchdog := mailcowSettings(mailConfig).Watchdog
+	if watchdog == nil || watchdog.Ntfy == nil {
+		return defaultWatchdogNtfyTopic
+	}
+	return defaults.GetOrDefault(watchdog.Ntfy.Topic, defaultWatchdogNtfyTopic)
+}
+
+// WatchdogNotifyEmail returns the external e-mail addresses notified by the watchdog.
+// mailConfig: Configuration related to mail services.
+func WatchdogNotifyEmail(mailConfig *mailConf.Config) []string {
+	watchdog := mailcowSettings(mailConfig).Watchdog
+	if watchdog == nil {
+		return nil
+	}
+	return watchdog.NotifyEmail
+}
+
+// validateWatchdogSettings validates the notification settings of the watchdog.
+// watchdog: The watchdog switches.
+func validateWatchdogSettings(watchdog *mailcowConf.WatchdogSettings) error {
+	if watchdog == nil {
+		return nil
+	}
+
+	for _, address := range watchdog.NotifyEmail {
+		if _, _, aErr := SplitAddress(address); aErr != nil || strings.ContainsAny(address, " ,'\"\\$`") {
+			return fmt.Errorf("mailcow watchdog notification address %s is invalid", address)
+		}
+	}
+	if watchdog.Subject != nil && strings.ContainsAny(*watchdog.Subject, "'\"\\$`\r\n") {
+		return fmt.Errorf("mailcow watchdog subject must not contain quotes, variables, or line breaks")
+	}
+	if watchdog.Ntfy != nil && watchdog.Ntfy.Topic != nil && !ntfyTopicPattern.MatchString(*watchdog.Ntfy.Topic) {
+		return fmt.Errorf("mailcow watchdog Ntfy topic %s is invalid", *watchdog.Ntfy.Topic)
+	}
+
+	return nil
+}
+
+// mailcowSettings returns the configured mailcow switches, or empty switches if none are configured.
+// mailConfig: Configuration related to mail services.
+func mailcowSettings(mailConfig *mailConf.Config) *mailcowConf.Settings {
+	if mailConfig.Mailcow == nil || mailConfig.Mailcow.Settings == nil {
+		return &mailcowConf.Settings{}
+	}
+	return mailConfig.Mailcow.Settings
+}
+
+// yesNo returns the mailcow representation ('y' or 'n') of a boolean.
+// value: The boolean.
+func yesNo(value bool) string {
+	if value {
+		return "y"
+	}
+	return "n"
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+)
+
+// defaultSmarthostPort is the default submission port of a smarthost.
+const defaultSmarthostPort = 587
+
+// defaultSPFPolicy is the default qualifier of the final 'all' SPF mechanism.
+const defaultSPFPolicy = "~all"
+
+// SmarthostHostname returns the hostname of the smarthost in the format expected by mailcow ('[host]:port').
+// smarthost: The smarthost configuration.
+func SmarthostHostname(smarthost *mailConf.SmarthostConfig) string {
+	return fmt.Sprintf("[%s]:%d", *smarthost.Host, defaults.GetOrDefault(smarthost.Port, defaultSmarthostPort))
+}
+
+// SmarthostForDomain returns the smarthost relaying the given domain, or nil if the domain sends directly.
+// A smarthost explicitly listing the domain takes precedence over a global smarthost.
+// mailConfig: Configuration related to mail services.
+// domain: The mail domain.
+func SmarthostForDomain(mailConfig *mailConf.Config, domain string) *mailConf.SmarthostConfig {
+	var global *mailConf.SmarthostConfig
+	for _, smarthost := range mailConfig.Smarthosts {
+		if len(smarthost.Domains) == 0 {
+			global = smarthost
+			continue
+		}
+		if slices.Contains(smarthost.Domains, domain) {
+			return smarthost
+		}
+	}
+	return global
+}
+
+// ValidateSmarthosts validates the smarthost configuration.
+// At most one smarthost may be global, and every domain may only be assigned to a single, managed smarthost.
+// mailConfig: Configuration related to mail services.
+func ValidateSmarthosts(mailConfig *mailConf.Config) error {
+	domains := Domains(mailConfig)
+	assigned := map[string]string{}
+	global := ""
+
+	for _, smarthost := range mailConfig.Smarthosts {
+		if smarthost.Host == nil || *smarthost.Host == "" {
+			return fmt.Errorf("smarthost is missing the host")
+		}
+		if smarthost.Username != nil && smarthost.PasswordSecret == nil {
+			return fmt.Errorf("smarthost %s requires the secret configuration key of its password", *smarthost.Host)
+		}
+		if len(smarthost.Domains) == 0 {
+			if global != "" {
+				return fmt.Errorf("only one global smarthost is allowed, found %s and %s", global, *smarthost.Host)
+			}
+			global = *smarthost.Host
+			continue
+		}
+		for _, domain := range smarthost.Domains {
+			if !slices.Contains(domains, domain) {
+				return fmt.Errorf("smarthost %s references the unmanaged domain %s", *smarthost.Host, domain)
+			}
+			if other, ok := assigned[domain]; ok {
+				return fmt.Errorf("domain %s is assigned to the smarthosts %s and %s", domain, other, *smarthost.Host)
+			}
+			assigned[domain] = *smarthost.Host
+		}
+	}
+
+	return nil
+}
+
+// SPFRecord returns the SPF record value for the given domain.
+// It authorizes the mail server and, if the domain relays through a smarthost, the smarthost's include.
+// mailConfig: Configuration related to mail services.
+// domain: The mail domain.
+func SPFRecord(mailConfig *mailConf.Config, domain string) string {
+	mechanisms := []string{"v=spf1", "mx", fmt.Sprintf("a:%s", Mailname(*mailConfig.Main.Name))}
+
+	smarthost := SmarthostForDomain(mailConfig, domain)
+	if smarthost != nil && smarthost.SPFInclude != nil {
+		mechanisms = append(mechanisms, fmt.Sprintf("include:%s", *smarthost.SPFInclude))
+	}
+
+	policy := defaultSPFPolicy
+	if mailConfig.SPF != nil {
+		policy = defaults.GetOrDefault(mailConfig.SPF.Policy, defaultSPFPolicy)
+	}
+	mechanisms = append(mechanisms, policy)
+
+	return fmt.Sprintf("\"%s\"", strings.Join(mechanisms, " "))
+}
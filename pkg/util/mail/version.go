@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+
+	mailConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/mail"
+)
+
+// mailcowVersionPattern matches the release tags of mailcow (e.g. '2026-07', '2026-07a').
+var mailcowVersionPattern = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}[a-z]?$`)
+
+// MailcowVersion returns the mailcow release to run.
+// mailConfig: Configuration related to mail services.
+func MailcowVersion(mailConfig *mailConf.Config) string {
+	return *mailConfig.Mailcow.Version
+}
+
+// ValidateMailcowVersion validates the mailcow release against the upstream release tag format.
+// mailConfig: Configuration related to mail services.
+func ValidateMailcowVersion(mailConfig *mailConf.Config) error {
+	if mailConfig.Mailcow == nil || mailConfig.Mailcow.Version == nil {
+		return fmt.Errorf("mailcow version is required")
+	}
+	if !mailcowVersionPattern.MatchString(*mailConfig.Mailcow.Version) {
+		return fmt.Errorf("mailcow version %s is not a release tag like '2026-07'", *mailConfig.Mailcow.Version)
+	}
+	return nil
+}
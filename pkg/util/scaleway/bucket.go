@@ -0,0 +1,63 @@
+package scaleway
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// principalPattern matches valid Scaleway principals.
+var principalPattern = regexp.MustCompile(`^(user_id|application_id):[0-9a-f-]{36}$`)
+
+// bucketPolicyVersion is the version of the Scaleway bucket policy language.
+const bucketPolicyVersion = "2023-04-17"
+
+// BucketPolicy returns the bucket policy granting an application the actions on a bucket and its objects.
+// The owners keep full access, because the policy denies access to all principals it doesn't list.
+// id: The identifier of the policy.
+// bucketName: The name of the bucket.
+// principal: The principal of the application.
+// actions: The actions granted to the application.
+// owners: The principals keeping full access.
+func BucketPolicy(id string, bucketName string, principal string, actions []string, owners []string) (string, error) {
+	resources := []string{bucketName, fmt.Sprintf("%s/*", bucketName)}
+	policy, mErr := json.Marshal(map[string]any{
+		"Version": bucketPolicyVersion,
+		"Id":      id,
+		"Statement": []map[string]any{
+			{
+				"Sid":       "ApplicationObjects",
+				"Effect":    "Allow",
+				"Principal": map[string]any{"SCW": principal},
+				"Action":    actions,
+				"Resource":  resources,
+			},
+			{
+				"Sid":       "Owners",
+				"Effect":    "Allow",
+				"Principal": map[string]any{"SCW": owners},
+				"Action":    []string{"*"},
+				"Resource":  resources,
+			},
+		},
+	})
+	if mErr != nil {
+		return "", mErr
+	}
+	return string(policy), nil
+}
+
+// ValidateOwnerPrincipals validates the principals keeping full access to a bucket with a policy.
+// At least one owner is required, as the policy would lock out the principal deploying it otherwise.
+// owners: The principals keeping full access.
+func ValidateOwnerPrincipals(owners []string) error {
+	if len(owners) == 0 {
+		return fmt.Errorf("at least one owner principal of the bucket is required")
+	}
+	for _, principal := range owners {
+		if !principalPattern.MatchString(principal) {
+			return fmt.Errorf("owner principal %s is invalid", principal)
+		}
+	}
+	return nil
+}
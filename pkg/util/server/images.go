@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+)
+
+// repositoryPatternRegexp matches the image verification patterns which are safe to render into a shell script.
+var repositoryPatternRegexp = regexp.MustCompile(`^[a-z0-9._:/*?-]+$`)
+
+// PinDigests returns whether the images are pinned to their digests.
+// serverConfig: The server configuration.
+func PinDigests(serverConfig *server.Config) bool {
+	if serverConfig.Images == nil {
+		return false
+	}
+	return defaults.GetOrDefault(serverConfig.Images.PinDigests, false)
+}
+
+// ValidateImages validates the pinning and verification of the images.
+// serverConfig: The server configuration.
+func ValidateImages(serverConfig *server.Config) error {
+	if serverConfig.Images == nil || len(serverConfig.Images.Verify) == 0 {
+		return nil
+	}
+
+	for _, policy := range serverConfig.Images.Verify {
+		repository := defaults.GetOrDefault(policy.Repository, "")
+		if repository == "" {
+			return fmt.Errorf("image verification policies require a repository pattern")
+		}
+		if _, mErr := path.Match(repository, ""); mErr != nil {
+			return fmt.Errorf("image verification pattern %s is invalid: %w", repository, mErr)
+		}
+		// the pattern is matched by the verification script on the server
+		if !repositoryPatternRegexp.MatchString(repository) {
+			return fmt.Errorf(
+				"image verification pattern %s may only contain registry and repository characters, '*', and '?'",
+				repository,
+			)
+		}
+
+		keyless := policy.Identity != nil || policy.Issuer != nil
+		switch {
+		case policy.Key != nil && keyless:
+			return fmt.Errorf("image verification policy %s requires either a key or a keyless identity, not both", repository)
+		case policy.Key == nil && (policy.Identity == nil || policy.Issuer == nil):
+			return fmt.Errorf("image verification policy %s requires a key or a keyless identity and issuer", repository)
+		}
+	}
+	return nil
+}
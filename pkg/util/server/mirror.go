@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/server"
+	scalewayUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/scaleway"
+)
+
+// MirrorURL is the URL Docker pulls Docker Hub images through if the registry mirror is enabled.
+const MirrorURL = "http://127.0.0.1:5000"
+
+// defaultMirrorTTL is the default duration cached images are kept after their last use.
+const defaultMirrorTTL = "168h"
+
+// MirrorEnabled returns whether the registry mirror is deployed and used by Docker.
+// serverConfig: The server configuration.
+func MirrorEnabled(serverConfig *server.Config) bool {
+	if serverConfig.RegistryMirror == nil {
+		return false
+	}
+	return defaults.GetOrDefault(serverConfig.RegistryMirror.Enabled, false)
+}
+
+// MirrorRegion returns the Scaleway region of the bucket storing the cached images.
+// serverConfig: The server configuration.
+// fallback: The region to use if none is configured.
+func MirrorRegion(serverConfig *server.Config, fallback string) string {
+	if serverConfig.RegistryMirror == nil {
+		return fallback
+	}
+	return defaults.GetOrDefault(serverConfig.RegistryMirror.Region, fallback)
+}
+
+// MirrorTTL returns the duration cached images are kept after their last use.
+// serverConfig: The server configuration.
+func MirrorTTL(serverConfig *server.Config) string {
+	if serverConfig.RegistryMirror == nil {
+		return defaultMirrorTTL
+	}
+	return defaults.GetOrDefault(serverConfig.RegistryMirror.TTL, defaultMirrorTTL)
+}
+
+// ValidateMirror validates the registry mirror configuration.
+// serverConfig: The server configuration.
+func ValidateMirror(serverConfig *server.Config) error {
+	if !MirrorEnabled(serverConfig) {
+		return nil
+	}
+
+	ttl := MirrorTTL(serverConfig)
+	if duration, pErr := time.ParseDuration(ttl); pErr != nil || duration <= 0 {
+		return fmt.Errorf("registry mirror ttl %s must be a positive duration (e.g. '168h')", ttl)
+	}
+	if (serverConfig.RegistryMirror.Username == nil) != (serverConfig.RegistryMirror.PasswordSecret == nil) {
+		return fmt.Errorf("registry mirror requires both a Docker Hub username and password secret, or neither")
+	}
+	if pErr := scalewayUtil.ValidateOwnerPrincipals(serverConfig.RegistryMirror.OwnerPrincipals); pErr != nil {
+		return fmt.Errorf("registry mirror: %w", pErr)
+	}
+	return nil
+}
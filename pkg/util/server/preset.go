@@ -0,0 +1,53 @@
+package server
+
+import "strings"
+
+// defaultPreset is the resource preset of unknown server types.
+const defaultPreset = "medium"
+
+// serverTypePresets maps the Hetzner cloud server types to their resource presets.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var serverTypePresets = map[string]string{
+	"cx22":  "small",
+	"cx23":  "small",
+	"cpx11": "small",
+	"cpx21": "small",
+	"cax11": "small",
+	"cx32":  "medium",
+	"cx33":  "medium",
+	"cpx31": "medium",
+	"cax21": "medium",
+	"ccx13": "medium",
+	"cx42":  "large",
+	"cx43":  "large",
+	"cx52":  "large",
+	"cx53":  "large",
+	"cpx41": "large",
+	"cpx51": "large",
+	"cax31": "large",
+	"cax41": "large",
+	"ccx23": "large",
+	"ccx33": "large",
+	"ccx43": "large",
+	"ccx53": "large",
+	"ccx63": "large",
+}
+
+// Preset returns the resource preset ('small', 'medium', or 'large') of a Hetzner cloud server type.
+// serverType: The Hetzner cloud server type.
+func Preset(serverType string) string {
+	if preset, ok := serverTypePresets[serverType]; ok {
+		return preset
+	}
+	return defaultPreset
+}
+
+// Arch returns the CPU architecture of a Hetzner cloud server type as GOARCH; only the CAX types are Arm64 based.
+// serverType: The Hetzner cloud server type.
+func Arch(serverType string) string {
+	if strings.HasPrefix(strings.ToLower(serverType), "cax") {
+		return "arm64"
+	}
+	return "amd64"
+}
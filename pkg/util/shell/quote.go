@@ -0,0 +1,12 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quote quotes a value to be safely used as a single argument in a POSIX shell script.
+// value: The value to quote.
+func Quote(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", `'\''`))
+}
@@ -0,0 +1,62 @@
+package simplelogin
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+)
+
+const (
+	// defaultBackupRetentionDays is the default number of days backups are kept.
+	defaultBackupRetentionDays = 3
+	// defaultArchiveTimeout is the default time in seconds after which a WAL segment is archived.
+	defaultArchiveTimeout = 300
+	// minArchiveTimeout is the minimal archive timeout in seconds, as every archived segment takes 16 MB.
+	minArchiveTimeout = 60
+)
+
+// BackupRetentionDays returns the number of days backups are kept.
+// simpleloginConfig: Configuration for SimpleLogin.
+func BackupRetentionDays(simpleloginConfig *simpleloginConf.Config) int {
+	return defaults.GetOrDefault(backupConfig(simpleloginConfig).RetentionDays, defaultBackupRetentionDays)
+}
+
+// PITREnabled returns whether the point-in-time recovery of the database is enabled.
+// simpleloginConfig: Configuration for SimpleLogin.
+func PITREnabled(simpleloginConfig *simpleloginConf.Config) bool {
+	pitr := backupConfig(simpleloginConfig).PITR
+	return pitr != nil && defaults.GetOrDefault(pitr.Enabled, false)
+}
+
+// PITRArchiveTimeout returns the time in seconds after which a WAL segment is archived.
+// simpleloginConfig: Configuration for SimpleLogin.
+func PITRArchiveTimeout(simpleloginConfig *simpleloginConf.Config) int {
+	pitr := backupConfig(simpleloginConfig).PITR
+	if pitr == nil {
+		return defaultArchiveTimeout
+	}
+	return defaults.GetOrDefault(pitr.ArchiveTimeout, defaultArchiveTimeout)
+}
+
+// ValidateBackup validates the backup of the SimpleLogin database.
+// simpleloginConfig: Configuration for SimpleLogin.
+func ValidateBackup(simpleloginConfig *simpleloginConf.Config) error {
+	if BackupRetentionDays(simpleloginConfig) < 1 {
+		return fmt.Errorf("simplelogin backups must be kept for at least one day")
+	}
+	if PITRArchiveTimeout(simpleloginConfig) < minArchiveTimeout {
+		return fmt.Errorf("simplelogin WAL archive timeout must be at least %d seconds", minArchiveTimeout)
+	}
+	return nil
+}
+
+// backupConfig returns the configured backup, or an empty backup if none is configured.
+// simpleloginConfig: Configuration for SimpleLogin.
+func backupConfig(simpleloginConfig *simpleloginConf.Config) *simpleloginConf.BackupConfig {
+	if simpleloginConfig.Backup == nil {
+		return &simpleloginConf.BackupConfig{}
+	}
+	return simpleloginConfig.Backup
+}
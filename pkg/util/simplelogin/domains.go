@@ -0,0 +1,108 @@
+package simplelogin
+
+import (
+	"fmt"
+	"slices"
+
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+)
+
+// handlerPort is the port the SimpleLogin email handler listens on.
+const handlerPort = 20381
+
+// RelayDomain returns the domain SimpleLogin creates aliases and reverse aliases in.
+// simpleloginConfig: Configuration for SimpleLogin.
+func RelayDomain(simpleloginConfig *simpleloginConf.Config) string {
+	return fmt.Sprintf("relay.%s", *simpleloginConfig.Mail.Domain)
+}
+
+// AliasDomains returns the domains whose mail is delivered to the SimpleLogin email handler.
+// The relay domain comes first; the zone and project of additional domains default to the SimpleLogin mail ones.
+// simpleloginConfig: Configuration for SimpleLogin.
+func AliasDomains(simpleloginConfig *simpleloginConf.Config) []*simpleloginConf.AliasDomainConfig {
+	relayDomain := RelayDomain(simpleloginConfig)
+	domains := []*simpleloginConf.AliasDomainConfig{
+		{
+			Domain:  &relayDomain,
+			ZoneID:  simpleloginConfig.Mail.ZoneID,
+			Project: simpleloginConfig.Mail.Project,
+		},
+	}
+	for _, domain := range simpleloginConfig.Mail.AliasDomains {
+		aliasDomain := *domain
+		if aliasDomain.ZoneID == nil {
+			aliasDomain.ZoneID = simpleloginConfig.Mail.ZoneID
+		}
+		if aliasDomain.Project == nil {
+			aliasDomain.Project = simpleloginConfig.Mail.Project
+		}
+		domains = append(domains, &aliasDomain)
+	}
+	return domains
+}
+
+// AliasDomainNames returns the names of the domains whose mail is delivered to the SimpleLogin email handler.
+// simpleloginConfig: Configuration for SimpleLogin.
+func AliasDomainNames(simpleloginConfig *simpleloginConf.Config) []string {
+	names := []string{}
+	for _, domain := range AliasDomains(simpleloginConfig) {
+		names = append(names, *domain.Domain)
+	}
+	return names
+}
+
+// OtherAliasDomains returns the additional alias domains available to all users as a JSON list.
+// simpleloginConfig: Configuration for SimpleLogin.
+func OtherAliasDomains(simpleloginConfig *simpleloginConf.Config) string {
+	return aliasDomainList(simpleloginConfig, false)
+}
+
+// PremiumAliasDomains returns the additional alias domains only available to premium users as a JSON list.
+// simpleloginConfig: Configuration for SimpleLogin.
+func PremiumAliasDomains(simpleloginConfig *simpleloginConf.Config) string {
+	return aliasDomainList(simpleloginConfig, true)
+}
+
+// aliasDomainList returns the additional alias domains of the given tier as a JSON list.
+// simpleloginConfig: Configuration for SimpleLogin.
+// premium: Whether to list the premium domains.
+func aliasDomainList(simpleloginConfig *simpleloginConf.Config, premium bool) string {
+	names := []string{}
+	for _, domain := range simpleloginConfig.Mail.AliasDomains {
+		if (domain.Premium != nil && *domain.Premium) == premium {
+			names = append(names, *domain.Domain)
+		}
+	}
+	return jsonList(names)
+}
+
+// HandlerNexthop returns the Postfix next hop of the SimpleLogin email handler.
+// The handler is reached via the private address of the server, because the loopback address
+// of the Postfix container is not the server's.
+// serverIPv4: The private IPv4 address of the server.
+func HandlerNexthop(serverIPv4 string) string {
+	return fmt.Sprintf("[%s]:%d", serverIPv4, handlerPort)
+}
+
+// ValidateAliasDomains validates that the alias domains are set, unique, and don't collide with the mail domains.
+// simpleloginConfig: Configuration for SimpleLogin.
+// mailDomains: The mail domains managed in mailcow.
+func ValidateAliasDomains(simpleloginConfig *simpleloginConf.Config, mailDomains []string) error {
+	for _, domain := range simpleloginConfig.Mail.AliasDomains {
+		if domain.Domain == nil || *domain.Domain == "" {
+			return fmt.Errorf("simplelogin alias domains must have a domain")
+		}
+	}
+
+	seen := []string{*simpleloginConfig.Mail.Domain}
+	for _, domain := range AliasDomainNames(simpleloginConfig) {
+		if slices.Contains(seen, domain) {
+			return fmt.Errorf("simplelogin alias domain %s is configured more than once", domain)
+		}
+		if slices.Contains(mailDomains, domain) {
+			return fmt.Errorf("simplelogin alias domain %s must not be a mail domain", domain)
+		}
+		seen = append(seen, domain)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package simplelogin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+	"github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/mail"
+)
+
+// redirectDomainPattern matches valid redirect domains.
+var redirectDomainPattern = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+
+// oidcScopePattern matches valid OIDC scopes and claims.
+var oidcScopePattern = regexp.MustCompile(`^[A-Za-z0-9_.:/-]+$`)
+
+// iconPattern matches valid Font Awesome icon names.
+var iconPattern = regexp.MustCompile(`^fa-[a-z0-9-]+$`)
+
+// hibpAPIKeyPattern matches valid Have I Been Pwned API keys.
+var hibpAPIKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// defaultSupportName is the default sender name of the support e-mails.
+const defaultSupportName = "E-Mail Relay"
+
+// defaultNameserver is the default nameserver used for the DNS checks.
+const defaultNameserver = "1.1.1.1"
+
+// defaultOIDCScopes is the default list of requested OIDC scopes.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+// Settings returns the values of the SimpleLogin application settings.
+// The defaults reproduce a closed instance with premium users and OIDC login.
+// simpleloginConfig: Configuration for SimpleLogin.
+func Settings(simpleloginConfig *simpleloginConf.Config) map[string]any {
+	settings := simpleloginSettings(simpleloginConfig)
+	support := settings.Support
+	if support == nil {
+		support = &simpleloginConf.SupportSettings{}
+	}
+	oidc := settings.OIDC
+	if oidc == nil {
+		oidc = &simpleloginConf.OIDCSettings{}
+	}
+	features := settings.Features
+	if features == nil {
+		features = &simpleloginConf.FeatureSettings{}
+	}
+
+	nameservers := settings.Nameservers
+	if len(nameservers) == 0 {
+		nameservers = []string{defaultNameserver}
+	}
+	scopes := oidc.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOIDCScopes
+	}
+
+	return map[string]any{
+		"disableRegistration":    defaults.GetOrDefault(settings.DisableRegistration, true),
+		"disableOnboarding":      defaults.GetOrDefault(settings.DisableOnboarding, true),
+		"premiumByDefault":       defaults.GetOrDefault(settings.PremiumByDefault, true),
+		"allowedRedirectDomains": jsonList(settings.AllowedRedirectDomains),
+		"nameservers":            strings.Join(nameservers, ","),
+		"enforceSpf":             defaults.GetOrDefault(settings.EnforceSPF, true),
+		"support": map[string]any{
+			"name":  defaults.GetOrDefault(support.Name, defaultSupportName),
+			"email": defaults.GetOrDefault(support.Email, fmt.Sprintf("noreply@%s", *simpleloginConfig.Mail.Domain)),
+		},
+		"oidc": map[string]any{
+			"scopes":    strings.Join(scopes, " "),
+			"nameField": defaults.GetOrDefault(oidc.NameField, "name"),
+			"icon":      defaults.GetOrDefault(oidc.Icon, "fa-cloud"),
+		},
+		"features": map[string]any{
+			"aliasSuffix": defaults.GetOrDefault(features.AliasSuffix, false),
+			"pgp":         defaults.GetOrDefault(features.PGP, true),
+			"hibpApiKeys": jsonList(features.HIBPAPIKeys),
+			"hibp":        len(features.HIBPAPIKeys) > 0,
+		},
+	}
+}
+
+// ValidateSettings validates the SimpleLogin application settings.
+// The values are rendered into the env file and must not break its quoting.
+// simpleloginConfig: Configuration for SimpleLogin.
+func ValidateSettings(simpleloginConfig *simpleloginConf.Config) error {
+	settings := simpleloginSettings(simpleloginConfig)
+
+	for _, domain := range settings.AllowedRedirectDomains {
+		if !redirectDomainPattern.MatchString(domain) {
+			return fmt.Errorf("simplelogin allowed redirect domain %s is invalid", domain)
+		}
+	}
+	for _, nameserver := range settings.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			return fmt.Errorf("simplelogin nameserver %s must be an IP address", nameserver)
+		}
+	}
+
+	if settings.Support != nil {
+		if settings.Support.Name != nil && strings.ContainsAny(*settings.Support.Name, "'\"\\$`\r\n") {
+			return fmt.Errorf("simplelogin support name must not contain quotes, variables, or line breaks")
+		}
+		if settings.Support.Email != nil {
+			_, _, aErr := mail.SplitAddress(*settings.Support.Email)
+			if aErr != nil || strings.ContainsAny(*settings.Support.Email, " ,'\"\\$`") {
+				return fmt.Errorf("simplelogin support address %s is invalid", *settings.Support.Email)
+			}
+		}
+	}
+
+	if oErr := validateOIDCSettings(settings.OIDC); oErr != nil {
+		return oErr
+	}
+
+	if settings.Features != nil {
+		for _, key := range settings.Features.HIBPAPIKeys {
+			if !hibpAPIKeyPattern.MatchString(key) {
+				return fmt.Errorf("simplelogin Have I Been Pwned API keys must be alphanumeric")
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateOIDCSettings validates the OIDC login settings.
+// oidc: The OIDC login settings.
+func validateOIDCSettings(oidc *simpleloginConf.OIDCSettings) error {
+	if oidc == nil {
+		return nil
+	}
+
+	if len(oidc.Scopes) > 0 && !slices.Contains(oidc.Scopes, "openid") {
+		return fmt.Errorf("simplelogin OIDC scopes must include 'openid'")
+	}
+	for _, scope := range oidc.Scopes {
+		if !oidcScopePattern.MatchString(scope) {
+			return fmt.Errorf("simplelogin OIDC scope %s is invalid", scope)
+		}
+	}
+	if oidc.NameField != nil && !oidcScopePattern.MatchString(*oidc.NameField) {
+		return fmt.Errorf("simplelogin OIDC name field %s is invalid", *oidc.NameField)
+	}
+	if oidc.Icon != nil && !iconPattern.MatchString(*oidc.Icon) {
+		return fmt.Errorf("simplelogin OIDC icon %s is invalid; use a Font Awesome icon (e.g. 'fa-cloud')", *oidc.Icon)
+	}
+
+	return nil
+}
+
+// simpleloginSettings returns the configured SimpleLogin settings, or empty settings if none are configured.
+// simpleloginConfig: Configuration for SimpleLogin.
+func simpleloginSettings(simpleloginConfig *simpleloginConf.Config) *simpleloginConf.Settings {
+	if simpleloginConfig.Settings == nil {
+		return &simpleloginConf.Settings{}
+	}
+	return simpleloginConfig.Settings
+}
+
+// jsonList returns the values as a JSON list.
+// values: The values.
+func jsonList(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	list, _ := json.Marshal(values)
+	return string(list)
+}
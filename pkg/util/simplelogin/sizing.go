@@ -0,0 +1,70 @@
+package simplelogin
+
+import (
+	"fmt"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+	serverUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/server"
+)
+
+// defaultWebTimeout is the default request timeout of the web interface in seconds.
+const defaultWebTimeout = 30
+
+// webPreset defines the sizing of the web interface of a preset.
+type webPreset struct {
+	// workers is the number of worker processes.
+	workers int
+	// threads is the number of threads per worker.
+	threads int
+}
+
+// webPresets maps the resource presets to the sizing of the web interface.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var webPresets = map[string]webPreset{
+	"small":  {workers: 1, threads: 2},
+	"medium": {workers: 2, threads: 2},
+	"large":  {workers: 4, threads: 4},
+}
+
+// WebSettings returns the sizing of the web interface.
+// The configured values take precedence over the preset of the server type.
+// simpleloginConfig: Configuration for SimpleLogin.
+// serverType: The Hetzner cloud server type.
+func WebSettings(simpleloginConfig *simpleloginConf.Config, serverType string) map[string]any {
+	web := simpleloginConfig.Web
+	if web == nil {
+		web = &simpleloginConf.WebConfig{}
+	}
+	preset := webPresets[serverUtil.Preset(serverType)]
+
+	return map[string]any{
+		"workers": defaults.GetOrDefault(web.Workers, preset.workers),
+		"threads": defaults.GetOrDefault(web.Threads, preset.threads),
+		"timeout": defaults.GetOrDefault(web.Timeout, defaultWebTimeout),
+	}
+}
+
+// RedisPersistence returns whether the Redis data is persisted and backed up.
+// simpleloginConfig: Configuration for SimpleLogin.
+func RedisPersistence(simpleloginConfig *simpleloginConf.Config) bool {
+	return simpleloginConfig.Redis != nil && defaults.GetOrDefault(simpleloginConfig.Redis.Persistence, false)
+}
+
+// ValidateWebSettings validates the sizing of the web interface.
+// simpleloginConfig: Configuration for SimpleLogin.
+func ValidateWebSettings(simpleloginConfig *simpleloginConf.Config) error {
+	web := simpleloginConfig.Web
+	if web == nil {
+		return nil
+	}
+
+	for _, value := range []*int{web.Workers, web.Threads, web.Timeout} {
+		if value != nil && *value <= 0 {
+			return fmt.Errorf("simplelogin web interface requires positive workers, threads, and timeout")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package simplelogin
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+
+	"github.com/muhlba91/pulumi-shared-library/pkg/util/defaults"
+
+	simpleloginConf "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/model/config/simplelogin"
+	scalewayUtil "github.com/muhlba91/muehlbachler-mail-services-infrastructure/pkg/util/scaleway"
+)
+
+const (
+	// StorageAWS stores the uploads in an AWS S3 bucket.
+	StorageAWS = "aws"
+	// StorageScaleway stores the uploads in a Scaleway Object Storage bucket.
+	StorageScaleway = "scaleway"
+	// StorageLocal stores the uploads in a volume on the server.
+	StorageLocal = "local"
+)
+
+// regionPattern matches valid bucket regions.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+(-[0-9]+)?$`)
+
+// storageBackends is the list of supported storage backends.
+//
+//nolint:gochecknoglobals // global is acceptable here
+var storageBackends = []string{StorageAWS, StorageScaleway, StorageLocal}
+
+// StorageBackend returns the storage backend of the SimpleLogin uploads.
+// simpleloginConfig: Configuration for SimpleLogin.
+func StorageBackend(simpleloginConfig *simpleloginConf.Config) string {
+	return defaults.GetOrDefault(storageConfig(simpleloginConfig).Backend, StorageAWS)
+}
+
+// StorageRegion returns the configured region of the bucket, or the given default region.
+// simpleloginConfig: Configuration for SimpleLogin.
+// defaultRegion: The default region of the backend.
+func StorageRegion(simpleloginConfig *simpleloginConf.Config, defaultRegion string) string {
+	return defaults.GetOrDefault(storageConfig(simpleloginConfig).Region, defaultRegion)
+}
+
+// StorageEndpoint returns the S3 endpoint of the bucket.
+// The endpoint of Scaleway defaults to the one of the region; AWS uses the SDK default unless configured.
+// simpleloginConfig: Configuration for SimpleLogin.
+// bucketRegion: The region of the bucket.
+func StorageEndpoint(simpleloginConfig *simpleloginConf.Config, bucketRegion string) string {
+	storage := storageConfig(simpleloginConfig)
+	if storage.Endpoint != nil {
+		return *storage.Endpoint
+	}
+	if StorageBackend(simpleloginConfig) == StorageScaleway {
+		return fmt.Sprintf("https://s3.%s.scw.cloud", bucketRegion)
+	}
+	return ""
+}
+
+// StorageOwnerPrincipals returns the Scaleway principals keeping full access to the bucket.
+// simpleloginConfig: Configuration for SimpleLogin.
+func StorageOwnerPrincipals(simpleloginConfig *simpleloginConf.Config) []string {
+	return storageConfig(simpleloginConfig).OwnerPrincipals
+}
+
+// ValidateStorage validates the object storage of the SimpleLogin uploads.
+// simpleloginConfig: Configuration for SimpleLogin.
+func ValidateStorage(simpleloginConfig *simpleloginConf.Config) error {
+	storage := storageConfig(simpleloginConfig)
+	backend := StorageBackend(simpleloginConfig)
+
+	if !slices.Contains(storageBackends, backend) {
+		return fmt.Errorf("simplelogin storage backend %s is invalid; use 'aws', 'scaleway', or 'local'", backend)
+	}
+	if backend == StorageLocal && storage.Endpoint != nil {
+		return fmt.Errorf("simplelogin local storage doesn't use an endpoint")
+	}
+	// the AWS bucket is created in the region of the provider
+	if backend != StorageScaleway && storage.Region != nil {
+		return fmt.Errorf("simplelogin storage region is only used by the Scaleway backend")
+	}
+	if storage.Region != nil && !regionPattern.MatchString(*storage.Region) {
+		return fmt.Errorf("simplelogin storage region %s is invalid", *storage.Region)
+	}
+	if storage.Endpoint != nil {
+		endpoint, uErr := url.Parse(*storage.Endpoint)
+		if uErr != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+			return fmt.Errorf("simplelogin storage endpoint %s must be an HTTPS URL", *storage.Endpoint)
+		}
+	}
+
+	if backend == StorageScaleway {
+		if pErr := scalewayUtil.ValidateOwnerPrincipals(storage.OwnerPrincipals); pErr != nil {
+			return fmt.Errorf("simplelogin Scaleway storage: %w", pErr)
+		}
+	} else if len(storage.OwnerPrincipals) > 0 {
+		return fmt.Errorf("simplelogin storage owner principals are only used by the Scaleway backend")
+	}
+
+	return nil
+}
+
+// storageConfig returns the configured storage, or an empty storage if none is configured.
+// simpleloginConfig: Configuration for SimpleLogin.
+func storageConfig(simpleloginConfig *simpleloginConf.Config) *simpleloginConf.StorageConfig {
+	if simpleloginConfig.Storage == nil {
+		return &simpleloginConf.StorageConfig{}
+	}
+	return simpleloginConfig.Storage
+}